@@ -131,7 +131,10 @@ func (ie *IfExpression)String()string{
 type FunctionLiteral struct {
 	Token lexer.Token //fn
 	Parameters []*Indetifier
+	ParameterTypes []TypeExpr //与Parameters一一对应，没有标注的为nil
+	ReturnType TypeExpr
 	Body *BlockStatement
+	IsGenerator bool //fn*声明的生成器
 }
 
 func (fn *FunctionLiteral)expressionNode(){}
@@ -142,14 +145,24 @@ func (fn *FunctionLiteral)String()string{
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p:= range fn.Parameters{
+	for i, p:= range fn.Parameters{
+		if i < len(fn.ParameterTypes) && fn.ParameterTypes[i] != nil{
+			params = append(params,p.String() + ":" + fn.ParameterTypes[i].String())
+			continue
+		}
 		params = append(params,p.String())
 	}
 
 	out.WriteString(fn.TokenLiteral())
+	if fn.IsGenerator{
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params,","))
 	out.WriteString(")")
+	if fn.ReturnType != nil{
+		out.WriteString("->" + fn.ReturnType.String())
+	}
 	out.WriteString(fn.Body.String())
 
 	return out.String()
@@ -196,6 +209,30 @@ func (s *StringLiteral)String()string{
 	return s.Token.Value
 }
 
+//插值字符串，"Hello ${name}!"，文本部分是StringLiteral
+type InterpolatedString struct {
+	Token lexer.Token
+	Parts []Expression
+}
+
+func (is *InterpolatedString)expressionNode(){}
+func (is *InterpolatedString)TokenLiteral()string{
+	return is.Token.Value
+}
+func (is *InterpolatedString)String()string{
+	var out bytes.Buffer
+
+	for _,part := range is.Parts{
+		if s,ok := part.(*StringLiteral);ok{
+			out.WriteString(s.Value)
+			continue
+		}
+		out.WriteString("${" + part.String() + "}")
+	}
+
+	return out.String()
+}
+
 //数组
 type ArrayLiteral struct {
 	Token lexer.Token
@@ -222,6 +259,34 @@ func (al *ArrayLiteral)String()string{
 }
 
 
+//元组，不可变
+type TupleLiteral struct {
+	Token lexer.Token
+	Element []Expression
+}
+
+func (tl *TupleLiteral)expressionNode()  {}
+func (tl *TupleLiteral)TokenLiteral()string{
+	return tl.Token.Value
+}
+func (tl *TupleLiteral)String()string{
+	var out bytes.Buffer
+
+	eles := []string{}
+	for _, el := range tl.Element{
+		eles = append(eles,el.String())
+	}
+
+	out.WriteString("(")
+	out.WriteString(strings.Join(eles,","))
+	if len(eles) == 1{
+		out.WriteString(",")
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
 //数组下标
 type IndexExpression struct {
 	Token lexer.Token
@@ -240,14 +305,66 @@ func (ie *IndexExpression)String()string{
 	out.WriteString(ie.Left.String())
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
-	out.WriteString("]")
+	out.WriteString("])")
+
+	return out.String()
+}
+
+//切片，x[a:b]，Low和High省略时为nil
+type SliceExpression struct {
+	Token lexer.Token
+	Left Expression
+	Low Expression
+	High Expression
+}
+
+func (se *SliceExpression)expressionNode(){}
+func (se *SliceExpression)TokenLiteral()string{
+	return se.Token.Value
+}
+func (se *SliceExpression)String()string{
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Low != nil{
+		out.WriteString(se.Low.String())
+	}
+	out.WriteString(":")
+	if se.High != nil{
+		out.WriteString(se.High.String())
+	}
+	out.WriteString("])")
 
 	return out.String()
 }
 
+//区间，a..b不包含b，a..=b包含b
+type RangeExpression struct {
+	Token lexer.Token
+	Start Expression
+	End Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression)expressionNode(){}
+func (re *RangeExpression)TokenLiteral()string{
+	return re.Token.Value
+}
+func (re *RangeExpression)String()string{
+	return "(" + re.Start.String() + re.TokenLiteral() + re.End.String() + ")"
+}
+
+//按源码中出现的顺序保存
+type HashLiteralPair struct {
+	Key Expression
+	Value Expression
+}
+
 type HashLiteral struct {
 	Token lexer.Token
-	Pairs map[Expression]Expression
+	Pairs []HashLiteralPair
 }
 
 func (h *HashLiteral)expressionNode(){}
@@ -258,8 +375,8 @@ func (h *HashLiteral)String()string{
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for k,v := range h.Pairs{
-		pairs = append(pairs,k.String()+":"+v.String())
+	for _,pair := range h.Pairs{
+		pairs = append(pairs,pair.Key.String()+":"+pair.Value.String())
 	}
 
 	out.WriteString("{")
@@ -268,3 +385,259 @@ func (h *HashLiteral)String()string{
 
 	return out.String()
 }
+
+//集合，{a,b,c}
+type SetLiteral struct {
+	Token lexer.Token
+	Element []Expression
+}
+
+func (sl *SetLiteral)expressionNode(){}
+func (sl *SetLiteral)TokenLiteral()string{
+	return sl.Token.Value
+}
+func (sl *SetLiteral)String()string{
+	var out bytes.Buffer
+
+	eles := []string{}
+	for _, el := range sl.Element{
+		eles = append(eles,el.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(eles,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//结构体构造，Point{x:1,y:2}
+type StructLiteralField struct {
+	Name *Indetifier
+	Value Expression
+}
+
+type StructLiteral struct {
+	Token lexer.Token
+	Name *Indetifier
+	Fields []StructLiteralField
+}
+
+func (sl *StructLiteral)expressionNode(){}
+func (sl *StructLiteral)TokenLiteral()string{
+	return sl.Token.Value
+}
+func (sl *StructLiteral)String()string{
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _,f := range sl.Fields{
+		fields = append(fields,f.Name.String()+":"+f.Value.String())
+	}
+
+	out.WriteString(sl.Name.String())
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//字段访问，p.x
+type FieldExpression struct {
+	Token lexer.Token
+	Left Expression
+	Field *Indetifier
+}
+
+func (fe *FieldExpression)expressionNode(){}
+func (fe *FieldExpression)TokenLiteral()string{
+	return fe.Token.Value
+}
+func (fe *FieldExpression)String()string{
+	return fe.Left.String() + "." + fe.Field.String()
+}
+
+//赋值，p.x = v
+type AssignExpression struct {
+	Token lexer.Token
+	Target Expression
+	Value Expression
+}
+
+func (ae *AssignExpression)expressionNode(){}
+func (ae *AssignExpression)TokenLiteral()string{
+	return ae.Token.Value
+}
+func (ae *AssignExpression)String()string{
+	return ae.Target.String() + "=" + ae.Value.String()
+}
+
+//方法调用，value.method(args)
+type MethodCallExpression struct {
+	Token lexer.Token
+	Receiver Expression
+	Method *Indetifier
+	Arguments []Expression
+}
+
+func (mc *MethodCallExpression)expressionNode(){}
+func (mc *MethodCallExpression)TokenLiteral()string{
+	return mc.Token.Value
+}
+func (mc *MethodCallExpression)String()string{
+	var out bytes.Buffer
+
+	args := []string{}
+	for _,a := range mc.Arguments{
+		args = append(args,a.String())
+	}
+
+	out.WriteString(mc.Receiver.String())
+	out.WriteString(".")
+	out.WriteString(mc.Method.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args,","))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+//match (x) { Circle(r) => r, Rect(w,h) if w == h => w, _ => 0 }
+type MatchArm struct {
+	Pattern Pattern
+	Guard Expression //没有guard时为nil
+	Body *BlockStatement
+}
+
+func (ma *MatchArm)String()string{
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil{
+		out.WriteString(" if " + ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+type MatchExpression struct {
+	Token lexer.Token
+	Subject Expression
+	Arms []*MatchArm
+}
+
+func (me *MatchExpression)expressionNode(){}
+func (me *MatchExpression)TokenLiteral()string{
+	return me.Token.Value
+}
+func (me *MatchExpression)String()string{
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _,a := range me.Arms{
+		arms = append(arms,a.String())
+	}
+
+	out.WriteString("match")
+	out.WriteString("(" + me.Subject.String() + ")")
+	out.WriteString("{")
+	out.WriteString(strings.Join(arms,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//import "path/to/mod"，值是模块导出的名字
+type ImportExpression struct {
+	Token lexer.Token
+	Path *StringLiteral
+}
+
+func (ie *ImportExpression)expressionNode(){}
+func (ie *ImportExpression)TokenLiteral()string{
+	return ie.Token.Value
+}
+func (ie *ImportExpression)String()string{
+	return ie.TokenLiteral() + " \"" + ie.Path.String() + "\""
+}
+
+//yield x，只能出现在fn*声明的生成器中，包括生成器里的内层函数
+type YieldExpression struct {
+	Token lexer.Token
+	Value Expression //yield后面没有值时为nil
+}
+
+func (ye *YieldExpression)expressionNode(){}
+func (ye *YieldExpression)TokenLiteral()string{
+	return ye.Token.Value
+}
+func (ye *YieldExpression)String()string{
+	if ye.Value == nil{
+		return ye.TokenLiteral()
+	}
+	return ye.TokenLiteral() + " " + ye.Value.String()
+}
+
+//spawn f(x)或spawn fn(){...}，在新的goroutine中调用，值是接收结果的通道
+type SpawnExpression struct {
+	Token lexer.Token
+	Call Expression
+}
+
+func (se *SpawnExpression)expressionNode(){}
+func (se *SpawnExpression)TokenLiteral()string{
+	return se.Token.Value
+}
+func (se *SpawnExpression)String()string{
+	return se.TokenLiteral() + " " + se.Call.String()
+}
+
+//select的分支：v = recv(c) => ...，send(c,x) => ...，默认分支_ => ...
+type SelectCase struct {
+	Token lexer.Token //recv、send或者_
+	Name *Indetifier //接收到的值绑定的名字，没有时为nil
+	Channel Expression //默认分支为nil
+	Value Expression //send的值，recv时为nil
+	Body *BlockStatement
+}
+
+func (sc *SelectCase)String()string{
+	var out bytes.Buffer
+
+	if sc.Name != nil{
+		out.WriteString(sc.Name.String() + "=")
+	}
+	out.WriteString(sc.Token.Value)
+	if sc.Channel != nil{
+		out.WriteString("(" + sc.Channel.String())
+		if sc.Value != nil{
+			out.WriteString("," + sc.Value.String())
+		}
+		out.WriteString(")")
+	}
+	out.WriteString(" => ")
+	out.WriteString(sc.Body.String())
+
+	return out.String()
+}
+
+type SelectExpression struct {
+	Token lexer.Token
+	Cases []*SelectCase
+}
+
+func (se *SelectExpression)expressionNode(){}
+func (se *SelectExpression)TokenLiteral()string{
+	return se.Token.Value
+}
+func (se *SelectExpression)String()string{
+	cases := []string{}
+	for _,c := range se.Cases{
+		cases = append(cases,c.String())
+	}
+
+	return "select{" + strings.Join(cases,",") + "}"
+}
@@ -0,0 +1,171 @@
+package ast
+
+import (
+	"lexer"
+	"bytes"
+	"strings"
+)
+
+//模式，用在match的分支和let解构里
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+//_，匹配任何值
+type WildcardPattern struct {
+	Token lexer.Token
+}
+
+func (wp *WildcardPattern)patternNode(){}
+func (wp *WildcardPattern)TokenLiteral()string{
+	return wp.Token.Value
+}
+func (wp *WildcardPattern)String()string{
+	return "_"
+}
+
+//小写的标识符，匹配任何值并绑定到这个名字
+type BindingPattern struct {
+	Name *Indetifier
+}
+
+func (bp *BindingPattern)patternNode(){}
+func (bp *BindingPattern)TokenLiteral()string{
+	return bp.Name.TokenLiteral()
+}
+func (bp *BindingPattern)String()string{
+	return bp.Name.String()
+}
+
+//整数、字符串和布尔字面量，值相等时匹配
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (lp *LiteralPattern)patternNode(){}
+func (lp *LiteralPattern)TokenLiteral()string{
+	return lp.Value.TokenLiteral()
+}
+func (lp *LiteralPattern)String()string{
+	return lp.Value.String()
+}
+
+//(a,b)，匹配相同长度的元组
+type TuplePattern struct {
+	Token lexer.Token
+	Elements []Pattern
+}
+
+func (tp *TuplePattern)patternNode(){}
+func (tp *TuplePattern)TokenLiteral()string{
+	return tp.Token.Value
+}
+func (tp *TuplePattern)String()string{
+	if len(tp.Elements) == 1{
+		return "(" + tp.Elements[0].String() + ",)"
+	}
+
+	return "(" + joinPatterns(tp.Elements) + ")"
+}
+
+//大写开头的名字是枚举的分支，Circle(r)或Shape.Circle(r)
+//没有字段的分支不带括号，Empty
+type VariantPattern struct {
+	Token lexer.Token
+	Enum *Indetifier //可以省略
+	Name *Indetifier
+	Fields []Pattern
+}
+
+func (vp *VariantPattern)patternNode(){}
+func (vp *VariantPattern)TokenLiteral()string{
+	return vp.Token.Value
+}
+func (vp *VariantPattern)String()string{
+	var out bytes.Buffer
+
+	if vp.Enum != nil{
+		out.WriteString(vp.Enum.String() + ".")
+	}
+	out.WriteString(vp.Name.String())
+
+	if len(vp.Fields) > 0{
+		out.WriteString("(" + joinPatterns(vp.Fields) + ")")
+	}
+
+	return out.String()
+}
+
+//[a,b,...rest]，没有rest时数组长度必须相同
+type ArrayPattern struct {
+	Token lexer.Token
+	Elements []Pattern
+	Rest Pattern //绑定或_，没有...时为nil
+}
+
+func (ap *ArrayPattern)patternNode(){}
+func (ap *ArrayPattern)TokenLiteral()string{
+	return ap.Token.Value
+}
+func (ap *ArrayPattern)String()string{
+	elements := []string{}
+	for _,e := range ap.Elements{
+		elements = append(elements,e.String())
+	}
+	if ap.Rest != nil{
+		elements = append(elements,"..." + ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements,",") + "]"
+}
+
+//{name,age:years}，name是{name:name}的简写
+type HashPatternEntry struct {
+	Key *StringLiteral
+	Value Pattern
+}
+
+type HashPattern struct {
+	Token lexer.Token
+	Entries []HashPatternEntry
+}
+
+func (hp *HashPattern)patternNode(){}
+func (hp *HashPattern)TokenLiteral()string{
+	return hp.Token.Value
+}
+func (hp *HashPattern)String()string{
+	entries := []string{}
+	for _,e := range hp.Entries{
+		if binding,ok := e.Value.(*BindingPattern);ok && binding.Name.Value == e.Key.Value{
+			entries = append(entries,e.Key.String())
+			continue
+		}
+		entries = append(entries,e.Key.String() + ":" + e.Value.String())
+	}
+
+	return "{" + strings.Join(entries,",") + "}"
+}
+
+//模式中绑定的所有名字，按出现的顺序
+func Bindings(p Pattern)[]*Indetifier{
+	names := []*Indetifier{}
+	Walk(p, func(node Node) bool {
+		if binding,ok := node.(*BindingPattern);ok{
+			names = append(names,binding.Name)
+		}
+		return true
+	})
+
+	return names
+}
+
+func joinPatterns(patterns []Pattern)string{
+	s := []string{}
+	for _,p := range patterns{
+		s = append(s,p.String())
+	}
+
+	return strings.Join(s,",")
+}
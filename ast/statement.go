@@ -3,6 +3,7 @@ package ast
 import (
 	"lexer"
 	"bytes"
+	"strings"
 )
 
 type Statement interface {
@@ -10,13 +11,29 @@ type Statement interface {
 	statmentNode()
 }
 
+//let x = v，或者解构let [a,b] = v，此时Name为nil
+//const声明也用LetStatement表示，Token是const
 type LetStatement struct {
 	Token lexer.Token
 	Name *Indetifier
+	Pattern Pattern
+	Type TypeExpr //let x: int = 1，没有标注时为nil
 	Value Expression
 }
 
 func (l *LetStatement)statmentNode(){}
+func (l *LetStatement)IsConst()bool{
+	return l.Token.Type == lexer.CONST
+}
+
+//声明的所有名字
+func (l *LetStatement)Names()[]*Indetifier{
+	if l.Pattern != nil{
+		return Bindings(l.Pattern)
+	}
+
+	return []*Indetifier{l.Name}
+}
 func (l *LetStatement)TokenLiteral()string{
 	return l.Token.Value
 }
@@ -24,7 +41,14 @@ func (l *LetStatement)String()string{
 	var out bytes.Buffer
 
 	out.WriteString(l.TokenLiteral() + " ")
-	out.WriteString(l.Name.String())
+	if l.Pattern != nil{
+		out.WriteString(l.Pattern.String())
+	}else{
+		out.WriteString(l.Name.String())
+	}
+	if l.Type != nil{
+		out.WriteString(":" + l.Type.String())
+	}
 	out.WriteString("=")
 
 	if l.Value != nil{
@@ -99,3 +123,118 @@ func (b *BlockStatement)TokenLiteral()string{
 func (b *BlockStatement)statmentNode(){
 
 }
+
+//结构体声明，struct Point { x, y }
+type StructStatement struct {
+	Token lexer.Token
+	Name *Indetifier
+	Fields []*Indetifier
+}
+
+func (s *StructStatement)statmentNode(){}
+func (s *StructStatement)TokenLiteral()string{
+	return s.Token.Value
+}
+func (s *StructStatement)String()string{
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _,f := range s.Fields{
+		fields = append(fields,f.String())
+	}
+
+	out.WriteString(s.TokenLiteral() + " ")
+	out.WriteString(s.Name.String())
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//枚举声明，enum Shape { Circle(r), Rect(w,h), Empty }
+type EnumVariant struct {
+	Name *Indetifier
+	Fields []*Indetifier
+}
+
+func (ev *EnumVariant)String()string{
+	if len(ev.Fields) == 0{
+		return ev.Name.String()
+	}
+
+	fields := []string{}
+	for _,f := range ev.Fields{
+		fields = append(fields,f.String())
+	}
+
+	return ev.Name.String() + "(" + strings.Join(fields,",") + ")"
+}
+
+type EnumStatement struct {
+	Token lexer.Token
+	Name *Indetifier
+	Variants []*EnumVariant
+}
+
+func (es *EnumStatement)statmentNode(){}
+func (es *EnumStatement)TokenLiteral()string{
+	return es.Token.Value
+}
+func (es *EnumStatement)String()string{
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _,v := range es.Variants{
+		variants = append(variants,v.String())
+	}
+
+	out.WriteString(es.TokenLiteral() + " ")
+	out.WriteString(es.Name.String())
+	out.WriteString("{")
+	out.WriteString(strings.Join(variants,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//导出声明，export let/const/struct/enum，只能出现在模块顶层
+type ExportStatement struct {
+	Token lexer.Token
+	Statement Statement
+}
+
+func (es *ExportStatement)statmentNode(){}
+func (es *ExportStatement)TokenLiteral()string{
+	return es.Token.Value
+}
+func (es *ExportStatement)String()string{
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+//导出的名字
+func (es *ExportStatement)Names()[]*Indetifier{
+	switch s := es.Statement.(type) {
+	case *LetStatement:
+		return s.Names()
+	case *StructStatement:
+		return []*Indetifier{s.Name}
+	case *EnumStatement:
+		return []*Indetifier{s.Name}
+	}
+	return nil
+}
+
+//defer f()，函数返回或生成器被关闭时求值
+type DeferStatement struct {
+	Token lexer.Token
+	Call Expression
+}
+
+func (ds *DeferStatement)statmentNode(){}
+func (ds *DeferStatement)TokenLiteral()string{
+	return ds.Token.Value
+}
+func (ds *DeferStatement)String()string{
+	return ds.TokenLiteral() + " " + ds.Call.String() + ";"
+}
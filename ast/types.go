@@ -0,0 +1,130 @@
+package ast
+
+import (
+	"lexer"
+	"strings"
+)
+
+//类型标注，let x: int、fn(a: [int]) -> bool
+type TypeExpr interface {
+	Node
+	typeNode()
+}
+
+//int、string、bool、null、any或者结构体、枚举的名字
+type NamedType struct {
+	Token lexer.Token
+	Name string
+}
+
+func (nt *NamedType)typeNode(){}
+func (nt *NamedType)TokenLiteral()string{
+	return nt.Token.Value
+}
+func (nt *NamedType)String()string{
+	return nt.Name
+}
+
+//[int]
+type ArrayType struct {
+	Token lexer.Token
+	Element TypeExpr
+}
+
+func (at *ArrayType)typeNode(){}
+func (at *ArrayType)TokenLiteral()string{
+	return at.Token.Value
+}
+func (at *ArrayType)String()string{
+	return "[" + at.Element.String() + "]"
+}
+
+//chan[int]，通道中值的类型
+type ChannelType struct {
+	Token lexer.Token
+	Element TypeExpr
+}
+
+func (ct *ChannelType)typeNode(){}
+func (ct *ChannelType)TokenLiteral()string{
+	return ct.Token.Value
+}
+func (ct *ChannelType)String()string{
+	return "chan[" + ct.Element.String() + "]"
+}
+
+//{string:int}
+type HashType struct {
+	Token lexer.Token
+	Key TypeExpr
+	Value TypeExpr
+}
+
+func (ht *HashType)typeNode(){}
+func (ht *HashType)TokenLiteral()string{
+	return ht.Token.Value
+}
+func (ht *HashType)String()string{
+	return "{" + ht.Key.String() + ":" + ht.Value.String() + "}"
+}
+
+//{int}
+type SetType struct {
+	Token lexer.Token
+	Element TypeExpr
+}
+
+func (st *SetType)typeNode(){}
+func (st *SetType)TokenLiteral()string{
+	return st.Token.Value
+}
+func (st *SetType)String()string{
+	return "{" + st.Element.String() + "}"
+}
+
+//(int,string)
+type TupleType struct {
+	Token lexer.Token
+	Elements []TypeExpr
+}
+
+func (tt *TupleType)typeNode(){}
+func (tt *TupleType)TokenLiteral()string{
+	return tt.Token.Value
+}
+func (tt *TupleType)String()string{
+	if len(tt.Elements) == 1{
+		return "(" + tt.Elements[0].String() + ",)"
+	}
+
+	return "(" + joinTypes(tt.Elements) + ")"
+}
+
+//fn(int,string) -> bool，没有->时返回值是any
+type FunctionType struct {
+	Token lexer.Token
+	Parameters []TypeExpr
+	Return TypeExpr
+}
+
+func (ft *FunctionType)typeNode(){}
+func (ft *FunctionType)TokenLiteral()string{
+	return ft.Token.Value
+}
+func (ft *FunctionType)String()string{
+	out := "fn(" + joinTypes(ft.Parameters) + ")"
+	if ft.Return != nil{
+		out += "->" + ft.Return.String()
+	}
+
+	return out
+}
+
+func joinTypes(types []TypeExpr)string{
+	s := []string{}
+	for _,t := range types{
+		s = append(s,t.String())
+	}
+
+	return strings.Join(s,",")
+}
@@ -0,0 +1,141 @@
+package ast
+
+//Walk深度优先遍历语法树，fn返回false时不再访问该节点的子节点
+//声明中的名字(let的变量名、参数、字段名等)不会被访问
+func Walk(node Node,fn func(Node)bool){
+	if node == nil || !fn(node){
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _,s := range node.Statements{
+			Walk(s,fn)
+		}
+	case *BlockStatement:
+		for _,s := range node.Statements{
+			Walk(s,fn)
+		}
+	case *LetStatement:
+		walkPattern(node.Pattern,fn)
+		walkExpression(node.Value,fn)
+	case *ReturnStatement:
+		walkExpression(node.ReturnValue,fn)
+	case *ExpressionStatement:
+		walkExpression(node.Expression,fn)
+	case *ExportStatement:
+		Walk(node.Statement,fn)
+	case *DeferStatement:
+		walkExpression(node.Call,fn)
+
+	case *PrefixExpression:
+		walkExpression(node.Right,fn)
+	case *InfixExpression:
+		walkExpression(node.Left,fn)
+		walkExpression(node.Right,fn)
+	case *IfExpression:
+		walkExpression(node.Condition,fn)
+		Walk(node.Consequence,fn)
+		if node.Alternative != nil{
+			Walk(node.Alternative,fn)
+		}
+	case *FunctionLiteral:
+		Walk(node.Body,fn)
+	case *CallExpression:
+		walkExpression(node.Function,fn)
+		walkExpressions(node.Arguments,fn)
+	case *InterpolatedString:
+		walkExpressions(node.Parts,fn)
+	case *ArrayLiteral:
+		walkExpressions(node.Element,fn)
+	case *TupleLiteral:
+		walkExpressions(node.Element,fn)
+	case *SetLiteral:
+		walkExpressions(node.Element,fn)
+	case *IndexExpression:
+		walkExpression(node.Left,fn)
+		walkExpression(node.Index,fn)
+	case *YieldExpression:
+		walkExpression(node.Value,fn)
+	case *RangeExpression:
+		walkExpression(node.Start,fn)
+		walkExpression(node.End,fn)
+	case *SliceExpression:
+		walkExpression(node.Left,fn)
+		walkExpression(node.Low,fn)
+		walkExpression(node.High,fn)
+	case *HashLiteral:
+		for _,pair := range node.Pairs{
+			walkExpression(pair.Key,fn)
+			walkExpression(pair.Value,fn)
+		}
+	case *StructLiteral:
+		Walk(node.Name,fn)
+		for _,f := range node.Fields{
+			walkExpression(f.Value,fn)
+		}
+	case *FieldExpression:
+		walkExpression(node.Left,fn)
+	case *AssignExpression:
+		walkExpression(node.Target,fn)
+		walkExpression(node.Value,fn)
+	case *MethodCallExpression:
+		walkExpression(node.Receiver,fn)
+		walkExpressions(node.Arguments,fn)
+	case *SpawnExpression:
+		walkExpression(node.Call,fn)
+	case *SelectExpression:
+		for _,c := range node.Cases{
+			if c.Name != nil{
+				Walk(c.Name,fn)
+			}
+			walkExpression(c.Channel,fn)
+			walkExpression(c.Value,fn)
+			Walk(c.Body,fn)
+		}
+	case *MatchExpression:
+		walkExpression(node.Subject,fn)
+		for _,arm := range node.Arms{
+			walkPattern(arm.Pattern,fn)
+			walkExpression(arm.Guard,fn)
+			Walk(arm.Body,fn)
+		}
+
+	case *TuplePattern:
+		for _,p := range node.Elements{
+			walkPattern(p,fn)
+		}
+	case *VariantPattern:
+		for _,p := range node.Fields{
+			walkPattern(p,fn)
+		}
+	case *ArrayPattern:
+		for _,p := range node.Elements{
+			walkPattern(p,fn)
+		}
+		walkPattern(node.Rest,fn)
+	case *HashPattern:
+		for _,e := range node.Entries{
+			walkPattern(e.Value,fn)
+		}
+	}
+}
+
+//guard、返回值等子节点可能为空
+func walkExpression(exp Expression,fn func(Node)bool){
+	if exp != nil{
+		Walk(exp,fn)
+	}
+}
+
+func walkExpressions(exps []Expression,fn func(Node)bool){
+	for _,e := range exps{
+		walkExpression(e,fn)
+	}
+}
+
+func walkPattern(p Pattern,fn func(Node)bool){
+	if p != nil{
+		Walk(p,fn)
+	}
+}
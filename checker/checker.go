@@ -0,0 +1,10 @@
+package checker
+
+import "ast"
+
+//Check对程序做静态检查，返回的警告不影响程序执行
+//builtins和globals的含义见Resolve，类型错误由TypeCheck单独检查
+func Check(program *ast.Program,builtins []string,globals []string)[]string{
+	warnings := Resolve(program,builtins,globals).Warnings
+	return append(warnings,checkMatches(program)...)
+}
@@ -0,0 +1,140 @@
+package checker
+
+import (
+	"ast"
+	"lexer"
+	"parser"
+	"testing"
+)
+
+func testParse(t *testing.T,input string)*ast.Program{
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0{
+		t.Fatalf("parse errors:%v",p.Errors())
+	}
+
+	return program
+}
+
+func checkWarnings(t *testing.T,input string,warnings []string,expected []string){
+	if len(warnings) != len(expected){
+		t.Errorf("%s:expected %v,got %v",input,expected,warnings)
+		return
+	}
+
+	for i,w := range warnings{
+		if w != expected[i]{
+			t.Errorf("%s:expected %q,got %q",input,expected[i],w)
+		}
+	}
+}
+
+func TestCheck_MatchExhaustive(t *testing.T){
+	enum := `enum Shape { Circle(r), Rect(w, h), Empty };`
+
+	tests := []struct{
+		input string
+		expected []string
+	}{
+		{`match (s) { Circle(r) => r, Rect(w, h) => w, Empty => 0 }`,nil},
+		{`match (s) { Circle(r) => r, _ => 0 }`,nil},
+		{`match (s) { Circle(r) => r, other => 0 }`,nil},
+		{`match (s) { Shape.Circle(_) => 1, Shape.Rect(w, h) => 2, Shape.Empty => 0 }`,nil},
+		{`match (s) { Circle(r) => r }`,
+			[]string{"match on Shape is not exhaustive,missing Rect,Empty"}},
+		{`match (s) { Circle(r) => r, Rect(w, h) if w == h => w, Empty => 0 }`,
+			[]string{"match on Shape is not exhaustive,missing Rect"}},
+		{`match (s) { Circle(1) => 1, Rect(w, h) => 2, Empty => 0 }`,
+			[]string{"match on Shape is not exhaustive,missing Circle"}},
+		{`let f = fn(s){ match (s) { Empty => 0 } }`,
+			[]string{"match on Shape is not exhaustive,missing Circle,Rect"}},
+		//不是枚举的match不检查
+		{`match (x) { 1 => "one", 2 => "two" }`,nil},
+		{`match (s) { Unknown => 0 }`,nil},
+	}
+
+	for _,tt := range tests{
+		warnings := checkMatches(testParse(t,enum + tt.input))
+		checkWarnings(t,tt.input,warnings,tt.expected)
+	}
+}
+
+func TestResolve(t *testing.T){
+	builtins := []string{"len","push"}
+
+	tests := []struct{
+		input string
+		expected []string
+	}{
+		{`let x = 1; len(x)`,nil},
+		{`let f = fn(n){ if (n < 1) { 0 } else { f(n - 1) } }; f(3)`,nil},
+		{`let f = fn(){ g() }; let g = fn(){ 1 };`,nil},
+		{`let add = fn(a, b){ a + b };`,nil},
+		{`y + 1`,[]string{"undefined name y"}},
+		{`let x = 1; "${x} and ${y}"`,[]string{"undefined name y"}},
+		{`let f = fn(){ y + y };`,[]string{"undefined name y"}},
+		{`let x = x;`,[]string{"undefined name x"}},
+		{`if (true) { let z = 1; }; z`,nil},
+		{`let f = fn(a, b){ a };`,[]string{"unused parameter b"}},
+		{`let f = fn(a, _b){ a };`,nil},
+		{`let f = fn(){ let t = 1; 2 };`,[]string{"unused variable t"}},
+		{`let f = fn(){ let [a, b] = [1, 2]; a };`,[]string{"unused variable b"}},
+		{`let f = fn(){ let x = 1; let g = fn(){ x }; g() };`,nil},
+		{`let len = 1;`,[]string{"variable len shadows builtin"}},
+		{`let f = fn(push){ push };`,[]string{"parameter push shadows builtin"}},
+		{`struct len { a }`,[]string{"type len shadows builtin"}},
+		{`let f = fn(){ let len = 1; len };`,[]string{"variable len shadows builtin"}},
+		{`let p = {"greet": fn(){ self.name }}; p.greet().upper()`,nil},
+		{`struct P { x }; let p = P{x: 1}; p.x = 2`,nil},
+		{`Q(1)`,[]string{"undefined name Q"}},
+		{`enum S { A(v), B }; match (S.A(1)) { A(v) if v > 0 => v, B => w }`,[]string{"undefined name w"}},
+		{`match (1) { n => { let m = n; 1 } }`,[]string{"unused variable m"}},
+		{`match (1) { len => len }`,[]string{"binding len shadows builtin"}},
+		{`let x = 1; let f = fn(){ let x = 2; x }; f()`,nil},
+		{`let c = 1; select { v = recv(c) => v, _ => w }`,[]string{"undefined name w"}},
+		{`select { v = recv(c) => v }`,[]string{"undefined name c"}},
+	}
+
+	for _,tt := range tests{
+		resolution := Resolve(testParse(t,tt.input),builtins,nil)
+		checkWarnings(t,tt.input,resolution.Warnings,tt.expected)
+	}
+}
+
+func TestResolve_Scopes(t *testing.T){
+	program := testParse(t,`let x = 1; let f = fn(x){ x + len(x) }; x`)
+	resolution := Resolve(program,[]string{"len"},[]string{"g"})
+
+	uses := map[string][]*Scope{}
+	for ident,scope := range resolution.Scopes{
+		uses[ident.Value] = append(uses[ident.Value],scope)
+	}
+
+	if len(uses["x"]) != 3{
+		t.Fatalf("expected 3 uses of x,got %d",len(uses["x"]))
+	}
+
+	global,local := 0,0
+	for _,scope := range uses["x"]{
+		if scope.Outer != nil && scope.Outer.Outer == nil{
+			global++
+		}else{
+			local++
+		}
+	}
+	if global != 1 || local != 2{
+		t.Errorf("expected 1 global and 2 local uses of x,got %d and %d",global,local)
+	}
+
+	if scope := uses["len"][0];scope.Outer != nil{
+		t.Errorf("len should resolve to the builtin scope")
+	}
+}
+
+func TestResolve_Globals(t *testing.T){
+	program := testParse(t,`a + b`)
+	resolution := Resolve(program,nil,[]string{"a"})
+
+	checkWarnings(t,"a + b",resolution.Warnings,[]string{"undefined name b"})
+}
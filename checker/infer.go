@@ -0,0 +1,907 @@
+package checker
+
+import (
+	"ast"
+	"fmt"
+	"parser"
+	"strings"
+)
+
+//渐进的类型检查：有标注的地方按标注检查，没有标注的代码用合一推断类型(Hindley-Milner)，
+//let绑定的函数会被泛化。推断不出来或者本来就是动态的地方是any，不会报错，
+//比如元素类型不同的数组是[any]，两个分支类型不同的if是any，
+//参数被当作多态函数使用(fn(g){ [g(1), g("a")] })的函数，没有标注的参数是any
+
+//内置函数的类型，单个小写字母是类型变量
+//参数个数可变的内置函数(比如trim)不在表中，按any检查
+var builtinSignatures = map[string]string{
+	"len":"fn(any) -> int",
+	"upper":"fn(string) -> string",
+	"lower":"fn(string) -> string",
+	"split":"fn(string, string) -> [string]",
+	"join":"fn([string], string) -> string",
+	"contains":"fn(string, string) -> bool",
+	"starts_with":"fn(string, string) -> bool",
+	"ends_with":"fn(string, string) -> bool",
+	"index_of":"fn(string, string) -> int",
+	"replace":"fn(string, string, string) -> string",
+	"repeat":"fn(string, int) -> string",
+	"bytes":"fn(string) -> [int]",
+	"first":"fn([a]) -> a",
+	"last":"fn([a]) -> a",
+	"rest":"fn([a]) -> [a]",
+	"reverse":"fn([a]) -> [a]",
+	"freeze":"fn(a) -> a",
+	"is_frozen":"fn(any) -> bool",
+	"error":"fn(string) -> any",
+	"send":"fn(chan[a], a) -> null",
+	"recv":"fn(chan[a]) -> a",
+}
+
+type scheme struct {
+	vars []*TVar
+	t Type
+}
+
+type typeEnv struct {
+	outer *typeEnv
+	names map[string]*scheme
+}
+
+func newTypeEnv(outer *typeEnv)*typeEnv{
+	return &typeEnv{outer:outer,names:map[string]*scheme{}}
+}
+
+func (e *typeEnv)get(name string)(*scheme,bool){
+	for env := e; env != nil; env = env.outer{
+		if s,ok := env.names[name];ok{
+			return s,true
+		}
+	}
+
+	return nil,false
+}
+
+func (e *typeEnv)set(name string,t Type){
+	e.names[name] = &scheme{t:t}
+}
+
+type returnType struct {
+	t Type
+	annotated bool
+}
+
+type typeChecker struct {
+	nextVar int
+	errors []string
+	types map[string]bool //声明过的结构体和枚举
+	returns []returnType
+	global *typeEnv
+	trail []*TVar //合一确定的类型变量，函数体推断失败时按它撤销
+}
+
+//TypeChecker在多次检查之间保留全局的类型，repl中每一行都用同一个
+type TypeChecker struct {
+	c *typeChecker
+}
+
+func NewTypeChecker()*TypeChecker{
+	c := &typeChecker{types:map[string]bool{}}
+
+	builtins := newTypeEnv(nil)
+	for name,signature := range builtinSignatures{
+		builtins.names[name] = c.builtinScheme(signature)
+	}
+	c.global = newTypeEnv(builtins)
+
+	return &TypeChecker{c:c}
+}
+
+//Check在执行前检查程序中的类型错误，有错误时这段程序中的声明不会保留
+func (tc *TypeChecker)Check(program *ast.Program)[]string{
+	c := tc.c
+	c.errors = nil
+	c.trail = nil
+
+	saved := map[string]*scheme{}
+	for name,s := range c.global.names{
+		saved[name] = s
+	}
+
+	ast.Walk(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.StructStatement:
+			c.types[node.Name.Value] = true
+		case *ast.EnumStatement:
+			c.types[node.Name.Value] = true
+		}
+		return true
+	})
+
+	for _,s := range program.Statements{
+		c.statement(s,c.global)
+	}
+
+	if len(c.errors) != 0{
+		c.global.names = saved
+	}
+
+	return c.errors
+}
+
+//TypeCheck单独检查一段程序
+func TypeCheck(program *ast.Program)[]string{
+	return NewTypeChecker().Check(program)
+}
+
+func (c *typeChecker)errorf(format string,a ...interface{}){
+	c.errors = append(c.errors,fmt.Sprintf(format,a...))
+}
+
+func (c *typeChecker)fresh()*TVar{
+	c.nextVar++
+	return &TVar{id:c.nextVar}
+}
+
+//合一成功时记录确定的类型变量
+func (c *typeChecker)unify(a,b Type)bool{
+	trail := []*TVar{}
+	if !unifyTrail(a,b,&trail){
+		for _,v := range trail{
+			v.instance = nil
+		}
+		return false
+	}
+
+	c.trail = append(c.trail,trail...)
+	return true
+}
+
+func (c *typeChecker)builtinScheme(signature string)*scheme{
+	te,errs := parser.ParseType(signature)
+	if len(errs) != 0{
+		panic("invalid builtin signature " + signature)
+	}
+
+	vars := map[string]*TVar{}
+	t := c.fromTypeExpr(te,vars)
+
+	s := &scheme{t:t}
+	for _,v := range vars{
+		s.vars = append(s.vars,v)
+	}
+
+	return s
+}
+
+//把类型标注转换为类型，vars不为nil时单个小写字母是类型变量
+func (c *typeChecker)fromTypeExpr(te ast.TypeExpr,vars map[string]*TVar)Type{
+	switch te := te.(type) {
+	case *ast.NamedType:
+		switch te.Name {
+		case "int":
+			return tInt
+		case "string":
+			return tString
+		case "bool":
+			return tBool
+		case "null":
+			return tNull
+		case "any":
+			return tAny
+		}
+		if vars != nil && len(te.Name) == 1 && te.Name[0] >= 'a' && te.Name[0] <= 'z'{
+			if _,ok := vars[te.Name];!ok{
+				vars[te.Name] = c.fresh()
+			}
+			return vars[te.Name]
+		}
+		if c.types[te.Name]{
+			return &TCon{Name:te.Name}
+		}
+		c.errorf("unknown type %s",te.Name)
+		return tAny
+	case *ast.ArrayType:
+		return &TArray{Element:c.fromTypeExpr(te.Element,vars)}
+	case *ast.HashType:
+		return &THash{Key:c.fromTypeExpr(te.Key,vars),Value:c.fromTypeExpr(te.Value,vars)}
+	case *ast.SetType:
+		return &TSet{Element:c.fromTypeExpr(te.Element,vars)}
+	case *ast.ChannelType:
+		return &TChan{Element:c.fromTypeExpr(te.Element,vars)}
+	case *ast.TupleType:
+		return &TTuple{Elements:c.fromTypeExprs(te.Elements,vars)}
+	case *ast.FunctionType:
+		fn := &TFunc{Params:c.fromTypeExprs(te.Parameters,vars),Return:tAny}
+		if te.Return != nil{
+			fn.Return = c.fromTypeExpr(te.Return,vars)
+		}
+		return fn
+	}
+
+	return tAny
+}
+
+func (c *typeChecker)fromTypeExprs(tes []ast.TypeExpr,vars map[string]*TVar)[]Type{
+	types := []Type{}
+	for _,te := range tes{
+		types = append(types,c.fromTypeExpr(te,vars))
+	}
+
+	return types
+}
+
+func (c *typeChecker)instantiate(s *scheme)Type{
+	if len(s.vars) == 0{
+		return s.t
+	}
+
+	mapping := map[*TVar]Type{}
+	for _,v := range s.vars{
+		mapping[v] = c.fresh()
+	}
+
+	return substitute(s.t,mapping)
+}
+
+func substitute(t Type,mapping map[*TVar]Type)Type{
+	switch t := prune(t).(type) {
+	case *TVar:
+		if replacement,ok := mapping[t];ok{
+			return replacement
+		}
+		return t
+	case *TArray:
+		return &TArray{Element:substitute(t.Element,mapping)}
+	case *THash:
+		return &THash{Key:substitute(t.Key,mapping),Value:substitute(t.Value,mapping)}
+	case *TSet:
+		return &TSet{Element:substitute(t.Element,mapping)}
+	case *TChan:
+		return &TChan{Element:substitute(t.Element,mapping)}
+	case *TTuple:
+		return &TTuple{Elements:substituteAll(t.Elements,mapping)}
+	case *TFunc:
+		return &TFunc{Params:substituteAll(t.Params,mapping),Return:substitute(t.Return,mapping)}
+	default:
+		return t
+	}
+}
+
+func substituteAll(types []Type,mapping map[*TVar]Type)[]Type{
+	result := make([]Type,len(types))
+	for i,t := range types{
+		result[i] = substitute(t,mapping)
+	}
+
+	return result
+}
+
+func freeVars(t Type,vars map[*TVar]bool){
+	switch t := prune(t).(type) {
+	case *TVar:
+		vars[t] = true
+	case *TArray:
+		freeVars(t.Element,vars)
+	case *THash:
+		freeVars(t.Key,vars)
+		freeVars(t.Value,vars)
+	case *TSet:
+		freeVars(t.Element,vars)
+	case *TChan:
+		freeVars(t.Element,vars)
+	case *TTuple:
+		for _,e := range t.Elements{
+			freeVars(e,vars)
+		}
+	case *TFunc:
+		for _,p := range t.Params{
+			freeVars(p,vars)
+		}
+		freeVars(t.Return,vars)
+	}
+}
+
+//环境中没有出现的类型变量可以泛化
+func (c *typeChecker)generalize(t Type,env *typeEnv)*scheme{
+	bound := map[*TVar]bool{}
+	for e := env; e != nil; e = e.outer{
+		for _,s := range e.names{
+			vars := map[*TVar]bool{}
+			freeVars(s.t,vars)
+			for _,v := range s.vars{
+				delete(vars,v)
+			}
+			for v := range vars{
+				bound[v] = true
+			}
+		}
+	}
+
+	vars := map[*TVar]bool{}
+	freeVars(t,vars)
+
+	s := &scheme{t:t}
+	for v := range vars{
+		if !bound[v]{
+			s.vars = append(s.vars,v)
+		}
+	}
+
+	return s
+}
+
+func (c *typeChecker)statement(stmt ast.Statement,env *typeEnv)Type{
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		c.letStatement(stmt,env)
+		return tAny
+
+	case *ast.ReturnStatement:
+		t := c.expression(stmt.ReturnValue,env)
+		c.checkReturn(t)
+		return t
+
+	case *ast.ExpressionStatement:
+		return c.expression(stmt.Expression,env)
+
+	case *ast.StructStatement:
+		params := make([]Type,len(stmt.Fields))
+		for i := range params{
+			params[i] = tAny
+		}
+		env.set(stmt.Name.Value,&TFunc{Params:params,Return:&TCon{Name:stmt.Name.Value}})
+		return tAny
+
+	case *ast.EnumStatement:
+		env.set(stmt.Name.Value,tAny)
+		return tAny
+
+	case *ast.ExportStatement:
+		return c.statement(stmt.Statement,env)
+
+	case *ast.DeferStatement:
+		c.expression(stmt.Call,env)
+		return tAny
+
+	case *ast.BlockStatement:
+		return c.block(stmt,env)
+	}
+
+	return tAny
+}
+
+//语句块的类型是最后一条语句的类型
+func (c *typeChecker)block(block *ast.BlockStatement,env *typeEnv)Type{
+	var t Type = tNull
+	for _,s := range block.Statements{
+		t = c.statement(s,env)
+	}
+
+	return t
+}
+
+func (c *typeChecker)letStatement(stmt *ast.LetStatement,env *typeEnv){
+	var annotation Type
+	if stmt.Type != nil{
+		annotation = c.fromTypeExpr(stmt.Type,nil)
+	}
+
+	//函数可以递归地引用自己
+	_,isFunction := stmt.Value.(*ast.FunctionLiteral)
+	var self *TVar
+	if isFunction && stmt.Name != nil{
+		self = c.fresh()
+		env.set(stmt.Name.Value,self)
+	}
+
+	t := c.expression(stmt.Value,env)
+	if self != nil{
+		c.unify(self,t)
+	}
+
+	if annotation != nil{
+		if !c.unify(annotation,t){
+			c.errorf("cannot use %s as %s in let %s",typeString(t),typeString(annotation),
+				strings.TrimPrefix(stmt.String(),stmt.TokenLiteral() + " "))
+		}
+		t = annotation
+	}
+
+	if stmt.Pattern != nil{
+		c.bindPattern(stmt.Pattern,t,env)
+		return
+	}
+
+	delete(env.names,stmt.Name.Value)
+	if isFunction{
+		env.names[stmt.Name.Value] = c.generalize(t,env)
+	}else{
+		env.set(stmt.Name.Value,t)
+	}
+}
+
+//模式中的名字按值的类型绑定，类型对不上时是any
+func (c *typeChecker)bindPattern(pattern ast.Pattern,t Type,env *typeEnv){
+	t = prune(t)
+
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		env.set(pattern.Name.Value,t)
+		return
+	case *ast.ArrayPattern:
+		if array,ok := t.(*TArray);ok{
+			for _,p := range pattern.Elements{
+				c.bindPattern(p,array.Element,env)
+			}
+			if pattern.Rest != nil{
+				c.bindPattern(pattern.Rest,array,env)
+			}
+			return
+		}
+	case *ast.TuplePattern:
+		if tuple,ok := t.(*TTuple);ok && len(tuple.Elements) == len(pattern.Elements){
+			for i,p := range pattern.Elements{
+				c.bindPattern(p,tuple.Elements[i],env)
+			}
+			return
+		}
+	case *ast.HashPattern:
+		if hash,ok := t.(*THash);ok && c.unify(hash.Key,tString){
+			for _,e := range pattern.Entries{
+				c.bindPattern(e.Value,hash.Value,env)
+			}
+			return
+		}
+	}
+
+	for _,name := range ast.Bindings(pattern){
+		env.set(name.Value,tAny)
+	}
+}
+
+func (c *typeChecker)checkReturn(t Type){
+	if len(c.returns) == 0{
+		return
+	}
+
+	ret := c.returns[len(c.returns)-1]
+	if !c.unify(ret.t,t) && ret.annotated{
+		c.errorf("cannot return %s from function returning %s",typeString(t),typeString(ret.t))
+	}
+}
+
+func (c *typeChecker)expression(exp ast.Expression,env *typeEnv)Type{
+	switch exp := exp.(type) {
+	case *ast.IntergerLiteral:
+		return tInt
+	case *ast.StringLiteral:
+		return tString
+	case *ast.InterpolatedString:
+		c.expressions(exp.Parts,env)
+		return tString
+	case *ast.Boolean:
+		return tBool
+
+	case *ast.Indetifier:
+		if s,ok := env.get(exp.Value);ok{
+			return c.instantiate(s)
+		}
+		return tAny
+
+	case *ast.PrefixExpression:
+		right := c.expression(exp.Right,env)
+		if exp.Operator == "!"{
+			return tBool
+		}
+		if !c.unify(right,tInt){
+			c.errorf("operator %s not supported for %s in %s",exp.Operator,typeString(right),exp.String())
+		}
+		return tInt
+
+	case *ast.InfixExpression:
+		return c.infixExpression(exp,env)
+
+	case *ast.IfExpression:
+		c.expression(exp.Condition,env)
+		consequence := c.block(exp.Consequence,env)
+		if exp.Alternative == nil{
+			return tAny
+		}
+		alternative := c.block(exp.Alternative,env)
+		if c.unify(consequence,alternative){
+			return consequence
+		}
+		return tAny
+
+	case *ast.FunctionLiteral:
+		return c.functionLiteral(exp,env)
+
+	case *ast.CallExpression:
+		return c.call(exp.Function,c.expression(exp.Function,env),c.expressions(exp.Arguments,env),exp.String())
+
+	case *ast.MethodCallExpression:
+		receiver := c.expression(exp.Receiver,env)
+		args := c.expressions(exp.Arguments,env)
+		return c.methodCall(exp,receiver,args)
+
+	case *ast.ArrayLiteral:
+		return &TArray{Element:c.common(c.expressions(exp.Element,env))}
+
+	case *ast.TupleLiteral:
+		return &TTuple{Elements:c.expressions(exp.Element,env)}
+
+	case *ast.SetLiteral:
+		return &TSet{Element:c.common(c.expressions(exp.Element,env))}
+
+	case *ast.HashLiteral:
+		keys,values := []Type{},[]Type{}
+		for _,pair := range exp.Pairs{
+			keys = append(keys,c.expression(pair.Key,env))
+			values = append(values,c.expression(pair.Value,env))
+		}
+		return &THash{Key:c.common(keys),Value:c.common(values)}
+
+	case *ast.IndexExpression:
+		return c.indexExpression(exp,env)
+
+	case *ast.SliceExpression:
+		return c.sliceExpression(exp,env)
+
+	case *ast.YieldExpression:
+		if exp.Value != nil{
+			c.expression(exp.Value,env)
+		}
+		return tNull
+
+	case *ast.RangeExpression:
+		for _,bound := range []ast.Expression{exp.Start,exp.End}{
+			if t := c.expression(bound,env);!c.unify(t,tInt){
+				c.errorf("cannot use %s as range bound in %s",typeString(t),exp.String())
+			}
+		}
+		return tAny
+
+	case *ast.StructLiteral:
+		for _,f := range exp.Fields{
+			c.expression(f.Value,env)
+		}
+		if c.types[exp.Name.Value]{
+			return &TCon{Name:exp.Name.Value}
+		}
+		return tAny
+
+	case *ast.FieldExpression:
+		c.expression(exp.Left,env)
+		return tAny
+
+	case *ast.AssignExpression:
+		c.expression(exp.Target,env)
+		return c.expression(exp.Value,env)
+
+	case *ast.MatchExpression:
+		return c.matchExpression(exp,env)
+
+	case *ast.SpawnExpression:
+		return c.spawnExpression(exp,env)
+
+	case *ast.SelectExpression:
+		return c.selectExpression(exp,env)
+	}
+
+	return tAny
+}
+
+func (c *typeChecker)expressions(exps []ast.Expression,env *typeEnv)[]Type{
+	types := []Type{}
+	for _,e := range exps{
+		types = append(types,c.expression(e,env))
+	}
+
+	return types
+}
+
+//所有类型都能合一时就是这个类型，否则是any，空的时候是新的类型变量
+func (c *typeChecker)common(types []Type)Type{
+	if len(types) == 0{
+		return c.fresh()
+	}
+
+	for _,t := range types[1:]{
+		if !c.unify(types[0],t){
+			return tAny
+		}
+	}
+
+	return types[0]
+}
+
+//与运行时一样，+可以用于整数或字符串，其他算术和比较运算只能用于整数，
+//==和!=两边的类型必须相同
+func (c *typeChecker)infixExpression(exp *ast.InfixExpression,env *typeEnv)Type{
+	left := c.expression(exp.Left,env)
+	right := c.expression(exp.Right,env)
+
+	mismatch := func(){
+		c.errorf("type mismatch in %s:%s %s %s",exp.String(),
+			typeString(left),exp.Operator,typeString(right))
+	}
+
+	switch exp.Operator {
+	case "+":
+		l,r := prune(left),prune(right)
+		if isAny(l) || isAny(r){
+			return tAny
+		}
+		for _,t := range []Type{l,r}{
+			if !isVar(t) && !c.unify(t,tInt) && !c.unify(t,tString){
+				c.errorf("operator + not supported for %s in %s",typeString(t),exp.String())
+				return tAny
+			}
+		}
+		if !c.unify(l,r){
+			mismatch()
+			return tAny
+		}
+		if isVar(prune(l)){
+			//两边都还不知道是整数还是字符串
+			return tAny
+		}
+		return l
+	case "-","*","/","<",">":
+		if !c.unify(left,tInt) || !c.unify(right,tInt){
+			if !c.unify(left,right){
+				mismatch()
+			}else{
+				c.errorf("operator %s not supported for %s in %s",exp.Operator,typeString(left),exp.String())
+			}
+		}
+		if exp.Operator == "<" || exp.Operator == ">"{
+			return tBool
+		}
+		return tInt
+	case "==","!=":
+		if !c.unify(left,right){
+			mismatch()
+		}
+		return tBool
+	}
+
+	return tAny
+}
+
+func isAny(t Type)bool{
+	_,ok := prune(t).(*TAny)
+	return ok
+}
+
+func isVar(t Type)bool{
+	_,ok := prune(t).(*TVar)
+	return ok
+}
+
+//函数体中有错误并且有没有标注的参数时，可能是参数要有多态的类型，秩1的推断做不到；
+//撤销这次推断，把没有标注的参数当作any重新检查
+func (c *typeChecker)functionLiteral(fn *ast.FunctionLiteral,env *typeEnv)Type{
+	errors,trail := len(c.errors),len(c.trail)
+
+	t,inferred := c.checkFunction(fn,env,false)
+	if len(c.errors) == errors || !inferred{
+		return t
+	}
+
+	for i := len(c.trail) - 1; i >= trail; i--{
+		c.trail[i].instance = nil
+	}
+	c.trail = c.trail[:trail]
+	c.errors = c.errors[:errors]
+
+	t,_ = c.checkFunction(fn,env,true)
+	return t
+}
+
+//dynamic为true时没有标注的参数是any，inferred表示有没有标注的参数
+func (c *typeChecker)checkFunction(fn *ast.FunctionLiteral,env *typeEnv,dynamic bool)(t Type,inferred bool){
+	fnEnv := newTypeEnv(env)
+	fnEnv.set("self",tAny)
+
+	params := []Type{}
+	for i,p := range fn.Parameters{
+		var t Type
+		if i < len(fn.ParameterTypes) && fn.ParameterTypes[i] != nil{
+			t = c.fromTypeExpr(fn.ParameterTypes[i],nil)
+		}else if dynamic{
+			t = tAny
+		}else{
+			t = c.fresh()
+			inferred = true
+		}
+		fnEnv.set(p.Value,t)
+		params = append(params,t)
+	}
+
+	ret := returnType{t:c.fresh()}
+	if fn.ReturnType != nil{
+		ret = returnType{t:c.fromTypeExpr(fn.ReturnType,nil),annotated:true}
+	}
+	//生成器调用后得到迭代器，不检查函数体的值
+	if fn.IsGenerator{
+		ret = returnType{t:tAny}
+	}
+
+	c.returns = append(c.returns,ret)
+	body := c.block(fn.Body,fnEnv)
+	c.checkReturn(body)
+	c.returns = c.returns[:len(c.returns)-1]
+
+	return &TFunc{Params:params,Return:ret.t},inferred
+}
+
+func (c *typeChecker)call(callee ast.Node,fnType Type,args []Type,context string)Type{
+	switch fn := prune(fnType).(type) {
+	case *TFunc:
+		if len(fn.Params) != len(args){
+			c.errorf("wrong number of arguments in %s:got %d,want %d",context,len(args),len(fn.Params))
+			return fn.Return
+		}
+		for i,arg := range args{
+			if !c.unify(fn.Params[i],arg){
+				c.errorf("cannot use %s as %s in argument %d of %s",
+					typeString(arg),typeString(fn.Params[i]),i+1,context)
+			}
+		}
+		return fn.Return
+	case *TVar:
+		ret := c.fresh()
+		c.unify(fn,&TFunc{Params:args,Return:ret})
+		return ret
+	case *TAny:
+		return tAny
+	default:
+		c.errorf("cannot call %s in %s",typeString(fn),context)
+		return tAny
+	}
+}
+
+//字符串和数组上的方法与同名的内置函数一样检查，接收者是第一个参数
+func (c *typeChecker)methodCall(exp *ast.MethodCallExpression,receiver Type,args []Type)Type{
+	switch receiver := prune(receiver).(type) {
+	case *TArray:
+	case *TCon:
+		if receiver.Name != "string"{
+			return tAny
+		}
+	default:
+		return tAny
+	}
+
+	signature,ok := builtinSignatures[exp.Method.Value]
+	if !ok{
+		return tAny
+	}
+
+	fn := c.instantiate(c.builtinScheme(signature))
+	return c.call(exp,fn,append([]Type{receiver},args...),exp.String())
+}
+
+func (c *typeChecker)indexExpression(exp *ast.IndexExpression,env *typeEnv)Type{
+	left := c.expression(exp.Left,env)
+	index := c.expression(exp.Index,env)
+
+	switch left := prune(left).(type) {
+	case *TArray:
+		if !c.unify(index,tInt){
+			c.errorf("cannot use %s as array index in %s",typeString(index),exp.String())
+		}
+		return left.Element
+	case *THash:
+		if !c.unify(left.Key,index){
+			c.errorf("cannot use %s as %s key in %s",typeString(index),typeString(left.Key),exp.String())
+		}
+		return left.Value
+	case *TTuple:
+		if lit,ok := exp.Index.(*ast.IntergerLiteral);ok && lit.Value >= 0 && lit.Value < int64(len(left.Elements)){
+			return left.Elements[lit.Value]
+		}
+	case *TCon:
+		//字符串的下标取得一个字符
+		if left.Name == tString.Name{
+			if !c.unify(index,tInt){
+				c.errorf("cannot use %s as string index in %s",typeString(index),exp.String())
+			}
+			return tString
+		}
+	}
+
+	return tAny
+}
+
+//数组和字符串的切片和原来的类型相同
+func (c *typeChecker)sliceExpression(exp *ast.SliceExpression,env *typeEnv)Type{
+	left := c.expression(exp.Left,env)
+
+	for _,bound := range []ast.Expression{exp.Low,exp.High}{
+		if bound == nil{
+			continue
+		}
+		if t := c.expression(bound,env);!c.unify(t,tInt){
+			c.errorf("cannot use %s as slice index in %s",typeString(t),exp.String())
+		}
+	}
+
+	switch left := prune(left).(type) {
+	case *TArray:
+		return left
+	case *TCon:
+		if left.Name == tString.Name{
+			return left
+		}
+	}
+
+	return tAny
+}
+
+//spawn的值是接收调用结果的通道
+func (c *typeChecker)spawnExpression(exp *ast.SpawnExpression,env *typeEnv)Type{
+	if _,ok := exp.Call.(*ast.CallExpression);ok{
+		return &TChan{Element:c.expression(exp.Call,env)}
+	}
+
+	ret := c.fresh()
+	if t := c.expression(exp.Call,env);!c.unify(t,&TFunc{Return:ret}){
+		c.errorf("cannot spawn %s in %s",typeString(t),exp.String())
+		return &TChan{Element:tAny}
+	}
+
+	return &TChan{Element:ret}
+}
+
+//recv分支绑定的名字是通道中值的类型，send的值要和通道一致
+func (c *typeChecker)selectExpression(exp *ast.SelectExpression,env *typeEnv)Type{
+	results := []Type{}
+	for _,sc := range exp.Cases{
+		caseEnv := newTypeEnv(env)
+		if sc.Channel != nil{
+			element := c.fresh()
+			if t := c.expression(sc.Channel,env);!c.unify(t,&TChan{Element:element}){
+				c.errorf("cannot use %s as channel in %s",typeString(t),exp.String())
+			}
+			if sc.Value != nil{
+				if t := c.expression(sc.Value,env);!c.unify(element,t){
+					c.errorf("cannot send %s to %s in %s",typeString(t),
+						typeString(&TChan{Element:element}),exp.String())
+				}
+			}
+			if sc.Name != nil{
+				caseEnv.set(sc.Name.Value,element)
+			}
+		}
+		results = append(results,c.block(sc.Body,caseEnv))
+	}
+
+	if len(results) == 0{
+		return tAny
+	}
+
+	return c.common(results)
+}
+
+func (c *typeChecker)matchExpression(exp *ast.MatchExpression,env *typeEnv)Type{
+	subject := c.expression(exp.Subject,env)
+
+	results := []Type{}
+	for _,arm := range exp.Arms{
+		armEnv := newTypeEnv(env)
+		c.bindPattern(arm.Pattern,subject,armEnv)
+		if arm.Guard != nil{
+			c.expression(arm.Guard,armEnv)
+		}
+		results = append(results,c.block(arm.Body,armEnv))
+	}
+
+	if len(results) == 0{
+		return tAny
+	}
+
+	return c.common(results)
+}
@@ -0,0 +1,199 @@
+package checker
+
+import "testing"
+
+//返回最后一条语句推断出的类型
+func testInfer(t *testing.T,input string)(string,[]string){
+	program := testParse(t,input)
+
+	c := NewTypeChecker().c
+
+	var last Type = tNull
+	for _,s := range program.Statements{
+		last = c.statement(s,c.global)
+	}
+
+	return typeString(last),c.errors
+}
+
+func TestInferTypes(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`1 + 2`,"int"},
+		{`"a" + "b"`,"string"},
+		{`1 < 2`,"bool"},
+		{`[1, 2]`,"[int]"},
+		{`[1, "a"]`,"[any]"},
+		{`[]`,"[a]"},
+		{`{"a": 1}`,"{string:int}"},
+		{`{1, 2}`,"{int}"},
+		{`(1, "a")`,"(int,string)"},
+		{`fn(x){ x }`,"fn(a)->a"},
+		{`fn(x, y){ x - y }`,"fn(int,int)->int"},
+		{`fn(x){ x + 1 }`,"fn(int)->int"},
+		{`fn(f, x){ f(x) }`,"fn(fn(a)->b,a)->b"},
+		{`let id = fn(x){ x }; (id(1), id("s"))`,"(int,string)"},
+		{`let fact = fn(n){ if (n < 2) { 1 } else { n * fact(n - 1) } }; fact`,"fn(int)->int"},
+		{`let compose = fn(f, g){ fn(x){ f(g(x)) } }; compose`,"fn(fn(a)->b,fn(c)->a)->fn(c)->b"},
+		{`let xs = [1, 2]; first(xs)`,"int"},
+		{`split("a,b", ",")`,"[string]"},
+		{`"abc".upper()`,"string"},
+		{`"abc"[0]`,"string"},
+		{`"abc"[1:]`,"string"},
+		{`[1, 2, 3][:-1]`,"[int]"},
+		{`bytes("abc")`,"[int]"},
+		{`let n = 1; "n=${n + 1}"`,"string"},
+		{`[1, 2].rest()`,"[int]"},
+		{`let h = {"a": [1]}; h["a"]`,"[int]"},
+		{`let [a, ...rest] = [1, 2]; rest`,"[int]"},
+		{`let (a, b) = (1, "s"); b`,"string"},
+		{`if (true) { 1 } else { "a" }`,"any"},
+		{`if (true) { 1 } else { 2 }`,"int"},
+		{`match (1) { 1 => "one", _ => "many" }`,"string"},
+		{`let f = fn(x: int) -> bool { x > 0 }; f`,"fn(int)->bool"},
+		{`let g: fn(int) -> int = fn(x){ x }; g`,"fn(int)->int"},
+		{`struct P { x }; P(1)`,"P"},
+		{`let x: any = 1; x`,"any"},
+		{`unknown(1)`,"any"},
+		{`let g = fn*(n){ yield n + 1 }; g`,"fn(int)->any"},
+		{`fn(g){ [g(1), g("a")] }`,"fn(any)->[any]"},
+		{`fn(g, n: int){ [g(n), g("a")] }`,"fn(any,int)->[any]"},
+		{`let f = fn(){ defer print(1); 2 }; f()`,"int"},
+		{`let c: chan[int] = chan(); c`,"chan[int]"},
+		{`let c: chan[int] = chan(); recv(c)`,"int"},
+		{`let f = fn(c){ send(c, "s") }; f`,"fn(chan[string])->null"},
+		{`spawn fn(){ 1 }`,"chan[int]"},
+		{`let sq = fn(x){ x * x }; spawn sq(2)`,"chan[int]"},
+		{`let c: chan[int] = chan(); select { v = recv(c) => v + 1, _ => 0 }`,"int"},
+	}
+
+	for _,tt := range tests{
+		typ,errors := testInfer(t,tt.input)
+		if len(errors) != 0{
+			t.Errorf("%s:unexpected errors %v",tt.input,errors)
+		}
+		if typ != tt.expected{
+			t.Errorf("%s:expected type %s,got %s",tt.input,tt.expected,typ)
+		}
+	}
+}
+
+func TestTypeCheck(t *testing.T){
+	tests := []struct{
+		input string
+		expected []string
+	}{
+		{`1 + "a"`,[]string{"type mismatch in (1+a):int + string"}},
+		{`"abc"["a"]`,[]string{"cannot use string as string index in (abc[a])"}},
+		{`[1, 2][true:]`,[]string{"cannot use bool as slice index in ([1,2][true:])"}},
+		{`0.."a"`,[]string{"cannot use string as range bound in (0..a)"}},
+		{`"${1 + "a"}"`,[]string{"type mismatch in (1+a):int + string"}},
+		{`let f = fn(x){ x + 1 }; f("a")`,[]string{"cannot use string as int in argument 1 of f(a)"}},
+		{`let f = fn(a: int, b: string) -> bool { a > 0 }; f(1, 2)`,
+			[]string{"cannot use int as string in argument 2 of f(1,2)"}},
+		{`let f = fn(a: int) -> bool { a }`,[]string{"cannot return int from function returning bool"}},
+		{`let f = fn(a: int) -> bool { if (a > 0) { return 1; }; true }`,
+			[]string{"cannot return int from function returning bool"}},
+		{`let x: string = 1;`,[]string{"cannot use int as string in let x:string=1;"}},
+		{`let f = fn(x){ x }; f(1, 2)`,[]string{"wrong number of arguments in f(1,2):got 2,want 1"}},
+		{`let x = 1; x(2)`,[]string{"cannot call int in x(2)"}},
+		{`-"a"`,[]string{"operator - not supported for string in (-a)"}},
+		{`true + true`,[]string{"operator + not supported for bool in (true+true)"}},
+		{`"a" - "b"`,[]string{"operator - not supported for string in (a-b)"}},
+		{`1 == "a"`,[]string{"type mismatch in (1==a):int == string"}},
+		{`let xs = [1, 2]; xs["a"]`,[]string{"cannot use string as array index in (xs[a])"}},
+		{`let h = {"a": 1}; h[1]`,[]string{"cannot use int as string key in (h[1])"}},
+		{`let x: foo = 1;`,[]string{"unknown type foo"}},
+		{`len(1, 2)`,[]string{"wrong number of arguments in len(1,2):got 2,want 1"}},
+		{`"abc".repeat("x")`,[]string{"cannot use string as int in argument 2 of abc.repeat(x)"}},
+		{`let c: chan[int] = chan(); send(c, "a")`,[]string{"cannot use string as int in argument 2 of send(c,a)"}},
+		{`spawn 1`,[]string{"cannot spawn int in spawn 1"}},
+		{`select { v = recv(1) => v }`,[]string{"cannot use int as channel in select{v=recv(1) => v}"}},
+		{`let c: chan[int] = chan(); select { send(c, "a") => 1 }`,
+			[]string{"cannot send string to chan[int] in select{send(c,a) => 1}"}},
+		{`let add = fn(a, b){ a + b }; add(1, "a")`,[]string{"cannot use string as int in argument 2 of add(1,a)"}},
+		{`let f = fn(x){ -"a" }; f(1)`,[]string{"operator - not supported for string in (-a)"}},
+		{`let f = fn(g, x: int){ [g(x), g("a")] }; f(fn(x){ x }, "b")`,
+			[]string{"cannot use string as int in argument 2 of f(fn(x)x,b)"}},
+
+		//动态的代码不报错
+		{`let add = fn(a, b){ a + b }; add(1, 2); add("a", "b")`,nil},
+		{`let xs = [1, "a", true]; len(xs)`,nil},
+		{`let f = fn(x){ if (x) { 1 } else { "a" } }; f(true) + 1`,nil},
+		{`let p = {"n": 1, "f": fn(){ self.n }}; p.f() + 1`,nil},
+		{`let g = fn(){ h() }; let h = fn(){ 1 }; g() + "a"`,nil},
+		{`let x: any = 1; x + "a"`,nil},
+		{`enum S { A(v) }; match (S.A(1)) { A(v) => v + 1 }`,nil},
+		//参数要有多态的类型时按any检查
+		{`let f = fn(g){ [g(1), g("a")] }; f(fn(x){ x })`,nil},
+		{`let n = 1; let f = fn(g){ [g(n), g("a")] }; n + 1`,nil},
+	}
+
+	for _,tt := range tests{
+		errors := TypeCheck(testParse(t,tt.input))
+		checkWarnings(t,tt.input,errors,tt.expected)
+	}
+}
+
+//每个内置函数按文档中的每种参数个数调用，都不应该报错
+func TestTypeCheck_BuiltinArities(t *testing.T){
+	calls := map[string][]string{
+		"len":{`len("abc")`,`len([1])`,`len({"a": 1})`},
+		"upper":{`upper("a")`,`"a".upper()`},
+		"lower":{`lower("A")`},
+		"trim":{`trim(" a ")`,`trim("xxhixx", "x")`,`"xxhixx".trim("x")`},
+		"split":{`split("a,b", ",")`},
+		"join":{`join(["a", "b"], ",")`},
+		"contains":{`contains("abc", "b")`},
+		"starts_with":{`starts_with("abc", "a")`},
+		"ends_with":{`ends_with("abc", "c")`},
+		"index_of":{`index_of("abc", "b")`},
+		"replace":{`replace("abc", "b", "x")`},
+		"repeat":{`repeat("a", 3)`},
+		"bytes":{`bytes("a")`},
+		"first":{`first([1])`},
+		"last":{`last([1])`},
+		"rest":{`rest([1])`},
+		"reverse":{`reverse([1])`},
+		"freeze":{`freeze([1])`},
+		"is_frozen":{`is_frozen([1])`},
+		"error":{`error("bad")`},
+		"send":{`let c: chan[int] = chan(); send(c, 1)`},
+		"recv":{`let c: chan[int] = chan(); recv(c) + 1`},
+	}
+
+	for name := range builtinSignatures{
+		if _,ok := calls[name];!ok{
+			t.Errorf("no calls for builtin %s",name)
+		}
+	}
+
+	for _,inputs := range calls{
+		for _,input := range inputs{
+			errors := TypeCheck(testParse(t,input))
+			checkWarnings(t,input,errors,nil)
+		}
+	}
+}
+
+func TestTypeChecker_KeepsGlobals(t *testing.T){
+	tc := NewTypeChecker()
+
+	lines := []struct{
+		input string
+		expected []string
+	}{
+		{`let f = fn(a: int) -> int { a * 2 };`,nil},
+		{`f("x")`,[]string{"cannot use string as int in argument 1 of f(x)"}},
+		{`let g: string = 1;`,[]string{"cannot use int as string in let g:string=1;"}},
+		//出错的那一行的声明没有保留
+		{`g + 1`,nil},
+		{`f(1) + 1`,nil},
+	}
+
+	for _,line := range lines{
+		checkWarnings(t,line.input,tc.Check(testParse(t,line.input)),line.expected)
+	}
+}
@@ -0,0 +1,131 @@
+package checker
+
+import (
+	"ast"
+	"fmt"
+	"strings"
+)
+
+//检查match是否覆盖了枚举的所有分支
+//没有guard的_、绑定或者字段都不做限制的分支模式才算覆盖，
+//嵌套的分支模式按不覆盖处理，所以检查是保守的
+func checkMatches(program *ast.Program)[]string{
+	enums := []*ast.EnumStatement{}
+	ast.Walk(program, func(node ast.Node) bool {
+		if enum,ok := node.(*ast.EnumStatement);ok{
+			enums = append(enums,enum)
+		}
+		return true
+	})
+
+	warnings := []string{}
+	ast.Walk(program, func(node ast.Node) bool {
+		if match,ok := node.(*ast.MatchExpression);ok{
+			if warning := checkExhaustive(match,enums);warning != ""{
+				warnings = append(warnings,warning)
+			}
+		}
+		return true
+	})
+
+	return warnings
+}
+
+func checkExhaustive(match *ast.MatchExpression,enums []*ast.EnumStatement)string{
+	variants := []*ast.VariantPattern{}
+	covered := map[string]bool{}
+
+	for _,arm := range match.Arms{
+		if arm.Guard == nil && irrefutable(arm.Pattern){
+			return ""
+		}
+
+		variant,ok := arm.Pattern.(*ast.VariantPattern)
+		if !ok{
+			continue
+		}
+		variants = append(variants,variant)
+
+		if arm.Guard == nil && allIrrefutable(variant.Fields){
+			covered[variant.Name.Value] = true
+		}
+	}
+
+	//不是对枚举的match，无法检查
+	enum := findEnum(variants,enums)
+	if enum == nil{
+		return ""
+	}
+
+	missing := []string{}
+	for _,v := range enum.Variants{
+		if !covered[v.Name.Value]{
+			missing = append(missing,v.Name.Value)
+		}
+	}
+
+	if len(missing) == 0{
+		return ""
+	}
+
+	return fmt.Sprintf("match on %s is not exhaustive,missing %s",
+		enum.Name.Value,strings.Join(missing,","))
+}
+
+//找到包含所有用到的分支的枚举
+func findEnum(variants []*ast.VariantPattern,enums []*ast.EnumStatement)*ast.EnumStatement{
+	if len(variants) == 0{
+		return nil
+	}
+
+	for _,enum := range enums{
+		if containsAll(enum,variants){
+			return enum
+		}
+	}
+
+	return nil
+}
+
+func containsAll(enum *ast.EnumStatement,variants []*ast.VariantPattern)bool{
+	for _,v := range variants{
+		if v.Enum != nil && v.Enum.Value != enum.Name.Value{
+			return false
+		}
+
+		found := false
+		for _,declared := range enum.Variants{
+			if declared.Name.Value == v.Name.Value{
+				found = true
+				break
+			}
+		}
+		if !found{
+			return false
+		}
+	}
+
+	return true
+}
+
+//总能匹配的模式
+func irrefutable(p ast.Pattern)bool{
+	switch p := p.(type) {
+	case *ast.WildcardPattern,*ast.BindingPattern:
+		return true
+	case *ast.TuplePattern:
+		return allIrrefutable(p.Elements)
+	default:
+		return false
+	}
+}
+
+func allIrrefutable(patterns []ast.Pattern)bool{
+	for _,p := range patterns{
+		if !irrefutable(p){
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,209 @@
+package checker
+
+import (
+	"ast"
+	"fmt"
+	"strings"
+)
+
+//作用域，内置函数在最外层，其次是全局作用域，
+//函数体和match的分支各自有一个作用域，if的语句块与外面共用
+type Scope struct {
+	Outer *Scope
+	names map[string]*declaration
+}
+
+func newScope(outer *Scope)*Scope{
+	return &Scope{Outer:outer,names:map[string]*declaration{}}
+}
+
+//返回声明了name的作用域，找不到时返回nil
+func (s *Scope)Lookup(name string)*Scope{
+	for scope := s; scope != nil; scope = scope.Outer{
+		if _,ok := scope.names[name];ok{
+			return scope
+		}
+	}
+
+	return nil
+}
+
+type declaration struct {
+	name string
+	kind string //variable,parameter,type,binding,self,builtin,global
+	used bool
+}
+
+type Resolution struct {
+	//使用处的标识符到声明它的作用域
+	Scopes map[*ast.Indetifier]*Scope
+	Warnings []string
+}
+
+type resolver struct {
+	resolution *Resolution
+	global *Scope
+
+	//函数体在外层作用域的语句都处理完之后再处理，
+	//这样函数可以引用在它之后声明的名字，和运行时一样
+	pending []func()
+	declared []*declaration
+	undefined map[string]bool
+}
+
+//Resolve在执行之前解析程序中所有的名字
+//builtins是内置函数，globals是已经定义的名字(比如repl之前输入的)
+//全局作用域的变量可能在之后被使用，不报告未使用
+func Resolve(program *ast.Program,builtins []string,globals []string)*Resolution{
+	universe := newScope(nil)
+	for _,name := range builtins{
+		universe.names[name] = &declaration{name:name,kind:"builtin"}
+	}
+
+	global := newScope(universe)
+	for _,name := range globals{
+		global.names[name] = &declaration{name:name,kind:"global"}
+	}
+
+	r := &resolver{
+		resolution:&Resolution{Scopes:map[*ast.Indetifier]*Scope{},Warnings:[]string{}},
+		global:global,
+		undefined:map[string]bool{},
+	}
+
+	r.body(program.Statements,global)
+
+	for _,d := range r.declared{
+		if d.used || strings.HasPrefix(d.name,"_"){
+			continue
+		}
+		r.warn("unused %s %s",d.kind,d.name)
+	}
+
+	return r.resolution
+}
+
+func (r *resolver)warn(format string,a ...interface{}){
+	r.resolution.Warnings = append(r.resolution.Warnings,fmt.Sprintf(format,a...))
+}
+
+func (r *resolver)body(stmts []ast.Statement,scope *Scope){
+	outer := r.pending
+	r.pending = nil
+
+	for _,s := range stmts{
+		r.node(s,scope)
+	}
+
+	for len(r.pending) > 0{
+		fns := r.pending
+		r.pending = nil
+		for _,fn := range fns{
+			fn()
+		}
+	}
+
+	r.pending = outer
+}
+
+func (r *resolver)declare(scope *Scope,name *ast.Indetifier,kind string){
+	if name.Value == "_"{
+		return
+	}
+
+	if builtin := scope.Lookup(name.Value);builtin != nil && builtin.Outer == nil{
+		r.warn("%s %s shadows builtin",kind,name.Value)
+	}
+
+	d := &declaration{name:name.Value,kind:kind}
+	scope.names[name.Value] = d
+
+	if (kind == "variable" || kind == "parameter") && scope != r.global{
+		r.declared = append(r.declared,d)
+	}
+}
+
+func (r *resolver)node(node ast.Node,scope *Scope){
+	switch node := node.(type) {
+	case *ast.Indetifier:
+		r.resolve(node,scope)
+
+	case *ast.LetStatement:
+		r.node(node.Value,scope)
+		for _,name := range node.Names(){
+			r.declare(scope,name,"variable")
+		}
+
+	case *ast.StructStatement:
+		r.declare(scope,node.Name,"type")
+
+	case *ast.EnumStatement:
+		r.declare(scope,node.Name,"type")
+
+	case *ast.FunctionLiteral:
+		fnScope := newScope(scope)
+		r.pending = append(r.pending, func() {
+			fnScope.names["self"] = &declaration{name:"self",kind:"self"}
+			for _,param := range node.Parameters{
+				r.declare(fnScope,param,"parameter")
+			}
+			r.body(node.Body.Statements,fnScope)
+		})
+
+	case *ast.SelectExpression:
+		for _,c := range node.Cases{
+			if c.Channel != nil{
+				r.node(c.Channel,scope)
+			}
+			if c.Value != nil{
+				r.node(c.Value,scope)
+			}
+			caseScope := newScope(scope)
+			if c.Name != nil{
+				r.declare(caseScope,c.Name,"binding")
+			}
+			for _,s := range c.Body.Statements{
+				r.node(s,caseScope)
+			}
+		}
+
+	case *ast.MatchExpression:
+		r.node(node.Subject,scope)
+		for _,arm := range node.Arms{
+			armScope := newScope(scope)
+			for _,name := range ast.Bindings(arm.Pattern){
+				r.declare(armScope,name,"binding")
+			}
+			if arm.Guard != nil{
+				r.node(arm.Guard,armScope)
+			}
+			for _,s := range arm.Body.Statements{
+				r.node(s,armScope)
+			}
+		}
+
+	default:
+		//其他节点不引入作用域，直接处理子节点
+		ast.Walk(node, func(child ast.Node) bool {
+			if child == node{
+				return true
+			}
+			r.node(child,scope)
+			return false
+		})
+	}
+}
+
+func (r *resolver)resolve(ident *ast.Indetifier,scope *Scope){
+	declaring := scope.Lookup(ident.Value)
+	if declaring == nil{
+		if !r.undefined[ident.Value]{
+			r.undefined[ident.Value] = true
+			r.warn("undefined name %s",ident.Value)
+		}
+		return
+	}
+
+	declaring.names[ident.Value].used = true
+	r.resolution.Scopes[ident] = declaring
+}
@@ -0,0 +1,246 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+//类型检查用到的类型
+type Type interface {
+	typ()
+}
+
+//int、string、bool、null以及结构体的名字
+type TCon struct {
+	Name string
+}
+
+//any可以和任何类型一起使用，没有标注又推断不出来的地方都是any
+type TAny struct{}
+
+//类型变量，instance不为nil时表示已经确定为这个类型
+type TVar struct {
+	id int
+	instance Type
+}
+
+type TArray struct {
+	Element Type
+}
+
+type THash struct {
+	Key Type
+	Value Type
+}
+
+type TSet struct {
+	Element Type
+}
+
+type TChan struct {
+	Element Type
+}
+
+type TTuple struct {
+	Elements []Type
+}
+
+type TFunc struct {
+	Params []Type
+	Return Type
+}
+
+func (t *TCon)typ(){}
+func (t *TAny)typ(){}
+func (t *TVar)typ(){}
+func (t *TArray)typ(){}
+func (t *THash)typ(){}
+func (t *TSet)typ(){}
+func (t *TChan)typ(){}
+func (t *TTuple)typ(){}
+func (t *TFunc)typ(){}
+
+var (
+	tInt = &TCon{Name:"int"}
+	tString = &TCon{Name:"string"}
+	tBool = &TCon{Name:"bool"}
+	tNull = &TCon{Name:"null"}
+	tAny = &TAny{}
+)
+
+//沿着已经确定的类型变量找到实际的类型
+//合一失败时要撤销，所以这里不压缩路径
+func prune(t Type)Type{
+	for{
+		v,ok := t.(*TVar)
+		if !ok || v.instance == nil{
+			return t
+		}
+		t = v.instance
+	}
+}
+
+func occurs(v *TVar,t Type)bool{
+	switch t := prune(t).(type) {
+	case *TVar:
+		return t == v
+	case *TArray:
+		return occurs(v,t.Element)
+	case *THash:
+		return occurs(v,t.Key) || occurs(v,t.Value)
+	case *TSet:
+		return occurs(v,t.Element)
+	case *TChan:
+		return occurs(v,t.Element)
+	case *TTuple:
+		return occursAny(v,t.Elements)
+	case *TFunc:
+		return occursAny(v,t.Params) || occurs(v,t.Return)
+	}
+
+	return false
+}
+
+func occursAny(v *TVar,types []Type)bool{
+	for _,t := range types{
+		if occurs(v,t){
+			return true
+		}
+	}
+
+	return false
+}
+
+//合一，失败时撤销这次合一中确定的类型变量
+func unify(a,b Type)bool{
+	trail := []*TVar{}
+	if unifyTrail(a,b,&trail){
+		return true
+	}
+
+	for _,v := range trail{
+		v.instance = nil
+	}
+
+	return false
+}
+
+func unifyTrail(a,b Type,trail *[]*TVar)bool{
+	a,b = prune(a),prune(b)
+
+	if _,ok := a.(*TAny);ok{
+		return true
+	}
+	if _,ok := b.(*TAny);ok{
+		return true
+	}
+
+	if v,ok := a.(*TVar);ok{
+		if v == b{
+			return true
+		}
+		if occurs(v,b){
+			return false
+		}
+		v.instance = b
+		*trail = append(*trail,v)
+		return true
+	}
+	if _,ok := b.(*TVar);ok{
+		return unifyTrail(b,a,trail)
+	}
+
+	switch a := a.(type) {
+	case *TCon:
+		other,ok := b.(*TCon)
+		return ok && a.Name == other.Name
+	case *TArray:
+		other,ok := b.(*TArray)
+		return ok && unifyTrail(a.Element,other.Element,trail)
+	case *THash:
+		other,ok := b.(*THash)
+		return ok && unifyTrail(a.Key,other.Key,trail) && unifyTrail(a.Value,other.Value,trail)
+	case *TSet:
+		other,ok := b.(*TSet)
+		return ok && unifyTrail(a.Element,other.Element,trail)
+	case *TChan:
+		other,ok := b.(*TChan)
+		return ok && unifyTrail(a.Element,other.Element,trail)
+	case *TTuple:
+		other,ok := b.(*TTuple)
+		return ok && unifyAll(a.Elements,other.Elements,trail)
+	case *TFunc:
+		other,ok := b.(*TFunc)
+		return ok && unifyAll(a.Params,other.Params,trail) && unifyTrail(a.Return,other.Return,trail)
+	}
+
+	return false
+}
+
+func unifyAll(a,b []Type,trail *[]*TVar)bool{
+	if len(a) != len(b){
+		return false
+	}
+
+	for i := range a{
+		if !unifyTrail(a[i],b[i],trail){
+			return false
+		}
+	}
+
+	return true
+}
+
+//类型的文本形式，未确定的类型变量按出现顺序显示为a、b、c...
+func typeString(t Type)string{
+	return (&typePrinter{names:map[*TVar]string{}}).print(t)
+}
+
+type typePrinter struct {
+	names map[*TVar]string
+}
+
+func (p *typePrinter)print(t Type)string{
+	switch t := prune(t).(type) {
+	case *TCon:
+		return t.Name
+	case *TAny:
+		return "any"
+	case *TVar:
+		if name,ok := p.names[t];ok{
+			return name
+		}
+		name := string(rune('a' + len(p.names) % 26))
+		if len(p.names) >= 26{
+			name += fmt.Sprint(len(p.names) / 26)
+		}
+		p.names[t] = name
+		return name
+	case *TArray:
+		return "[" + p.print(t.Element) + "]"
+	case *THash:
+		return "{" + p.print(t.Key) + ":" + p.print(t.Value) + "}"
+	case *TSet:
+		return "{" + p.print(t.Element) + "}"
+	case *TChan:
+		return "chan[" + p.print(t.Element) + "]"
+	case *TTuple:
+		if len(t.Elements) == 1{
+			return "(" + p.print(t.Elements[0]) + ",)"
+		}
+		return "(" + p.printAll(t.Elements) + ")"
+	case *TFunc:
+		return "fn(" + p.printAll(t.Params) + ")->" + p.print(t.Return)
+	}
+
+	return "?"
+}
+
+func (p *typePrinter)printAll(types []Type)string{
+	s := []string{}
+	for _,t := range types{
+		s = append(s,p.print(t))
+	}
+
+	return strings.Join(s,",")
+}
@@ -1,8 +1,16 @@
 package evaluator
 
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
 var builtins = map[string]*Builtin{
 	"len":&Builtin{
-		Fn: func(args ...Object) Object {
+		Fn: func(env *Environment,args ...Object) Object {
 			if len(args) != 1{
 				return newError("wrong number of arguments.got =%d," +
 					"want=1", len(args))
@@ -10,26 +18,216 @@ var builtins = map[string]*Builtin{
 
 			switch arg := args[0].(type) {
 			case *StringObject:
-				return &Integer{Value:int64(len(arg.Value))}
+				return &Integer{Value:int64(utf8.RuneCountInString(arg.Value))}
 			case *Array:
+				return &Integer{Value:int64(arg.Len())}
+			case *Tuple:
 				return &Integer{Value:int64(len(arg.Element))}
+			case *Hash:
+				return &Integer{Value:int64(arg.Len())}
+			case *Set:
+				return &Integer{Value:int64(arg.Len())}
+			case *Range:
+				return &Integer{Value:arg.Len()}
 			default:
 				return newError("the type not support " +
 					"len func")
 			}
-
-			return NULL
 		},
 	},
 	"print":{
-		Fn: func(args ...Object) Object {
+		Fn: func(env *Environment,args ...Object) Object {
+			return writeOutput(env,"print",args,"")
+		},
+	},
+	"println":{
+		Fn: func(env *Environment,args ...Object) Object {
+			return writeOutput(env,"println",args,"\n")
+		},
+	},
+}
+
+//多个任务同时print时，每次输出不会交错
+var outputMu sync.Mutex
+
+//参数之间用空格分隔，写到环境的输出中
+func writeOutput(env *Environment,name string,args []Object,end string)Object{
+	values := make([]string,len(args))
+	for i,arg := range args{
+		values[i] = arg.Inspect()
+	}
+
+	outputMu.Lock()
+	_,err := io.WriteString(env.Output(),strings.Join(values," ") + end)
+	outputMu.Unlock()
+	if err != nil{
+		return newError("%s:%s",name,err)
+	}
+
+	return NULL
+}
+func init(){
+	builtins["puts"] = builtins["println"]
 
+	//冻结数组、hash、集合或结构体，冻结后不能再修改，可以作为hash的key
+	builtins["freeze"] = &Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
 			if len(args) != 1{
-				return newError("wrong number of arguments.got =%d," +
-					"want=1", len(args))
+				return wrongArgumentCount(len(args),1)
 			}
 
-			return &StringObject{Value:args[0].Inspect()}
+			switch arg := args[0].(type) {
+			case *Array:
+				arg.Frozen = true
+			case *Hash:
+				arg.Frozen = true
+			case *Set:
+				arg.Frozen = true
+			case *Struct:
+				arg.Frozen = true
+			}
+
+			return args[0]
 		},
-	},
-}
\ No newline at end of file
+	}
+	builtins["is_frozen"] = &Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			return nativeBoolToBooleanObj(isFrozen(args[0]))
+		},
+	}
+
+	//返回以参数为消息的错误，标准库用它报告错误的参数
+	builtins["error"] = &Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			msg,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("error",0,STRING_OBJ,args[0])
+			}
+
+			return newError("%s",msg.Value)
+		},
+	}
+}
+
+//所有内置函数的名字
+func BuiltinNames()[]string{
+	names := make([]string,0,len(builtins))
+	for name := range builtins{
+		names = append(names,name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//返回冻结的深拷贝，原来的值不受影响
+func deepFreeze(obj Object)Object{
+	return (&freezer{seen:map[Object]Object{}}).freeze(obj)
+}
+
+//seen记录已经拷贝过的值，共享的和循环引用的值只拷贝一次
+//functions为true时函数也换成拷贝，见shareValue
+type freezer struct {
+	seen map[Object]Object
+	functions bool
+	err Object //遇到不能交给其他任务的值
+}
+
+func (f *freezer)freeze(obj Object)Object{
+	if frozen,ok := f.seen[obj];ok{
+		return frozen
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		frozen := &Array{elements:emptyVector,Frozen:true}
+		f.seen[obj] = frozen
+		elements := obj.Elements()
+		for i,e := range elements{
+			elements[i] = f.freeze(e)
+		}
+		frozen.elements = newVector(elements)
+		return frozen
+	case *Hash:
+		frozen := &Hash{pairs:emptyPmap,Frozen:true}
+		f.seen[obj] = frozen
+		obj.Each(func(key Hashable, value Object) bool {
+			frozen.Set(key,f.freeze(value))
+			return true
+		})
+		return frozen
+	case *Set:
+		//集合的元素都能作为key，已经是不可变的
+		frozen := &Set{members:obj.members.Copy(),Frozen:true}
+		f.seen[obj] = frozen
+		return frozen
+	case *Struct:
+		frozen := &Struct{Def:obj.Def,Values:make([]Object,len(obj.Values)),Frozen:true}
+		f.seen[obj] = frozen
+		for i,v := range obj.Values{
+			frozen.Values[i] = f.freeze(v)
+		}
+		return frozen
+	case *Tuple:
+		frozen := &Tuple{Element:make([]Object,len(obj.Element))}
+		f.seen[obj] = frozen
+		for i,e := range obj.Element{
+			frozen.Element[i] = f.freeze(e)
+		}
+		return frozen
+	case *EnumValue:
+		frozen := &EnumValue{Variant:obj.Variant,Values:make([]Object,len(obj.Values))}
+		f.seen[obj] = frozen
+		for i,v := range obj.Values{
+			frozen.Values[i] = f.freeze(v)
+		}
+		return frozen
+	case *Function:
+		if f.functions{
+			return f.shareFunction(obj)
+		}
+		return obj
+	case *Iterator:
+		//迭代器(包括生成器)的状态没有加锁，不能在两个任务中使用
+		if f.functions && f.err == nil{
+			f.err = newError("cannot share %s with another task",ITERATOR_OBJ)
+		}
+		return obj
+	default:
+		return obj
+	}
+}
+
+//基本类型和元组本身就是不可变的
+func isFrozen(obj Object)bool{
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.Frozen
+	case *Hash:
+		return obj.Frozen
+	case *Set:
+		return obj.Frozen
+	case *Struct:
+		return obj.Frozen
+	default:
+		return true
+	}
+}
+
+func wrongArgumentCount(got,want int)Object{
+	return newError("wrong number of arguments.got =%d," +
+		"want=%d", got, want)
+}
+
+func wrongArgumentType(name string,pos int,want ObjectType,got Object)Object{
+	return newError("argument %d to `%s` must be %s,got %s",
+		pos, name, want, got.Type())
+}
@@ -0,0 +1,253 @@
+package evaluator
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+//数组标准库
+//除push外都不修改原数组，返回新的数组。
+//push原地修改数组，引用同一个数组的变量和闭包都能看到新元素；
+//其他函数返回的数组与原数组共享底层的持久化向量，不受之后push的影响
+var arrayBuiltins = map[string]*Builtin{
+	"push":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) < 2{
+				return newError("wrong number of arguments.got =%d," +
+					"want at least 2", len(args))
+			}
+
+			arr,ok := args[0].(*Array)
+			if !ok{
+				return wrongArgumentType("push",0,ARRAY_OBJ,args[0])
+			}
+			if arr.Frozen{
+				return newError("cannot push to frozen array")
+			}
+
+			arr.Push(args[1:]...)
+			return arr
+		},
+	},
+	"first":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			arr,err := oneArrayArg("first",args)
+			if err != nil{
+				return err
+			}
+
+			if arr.Len() == 0{
+				return NULL
+			}
+
+			return arr.At(0)
+		},
+	},
+	"last":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			arr,err := oneArrayArg("last",args)
+			if err != nil{
+				return err
+			}
+
+			if arr.Len() == 0{
+				return NULL
+			}
+
+			return arr.At(arr.Len()-1)
+		},
+	},
+	"rest":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			arr,err := oneArrayArg("rest",args)
+			if err != nil{
+				return err
+			}
+
+			if arr.Len() == 0{
+				return NULL
+			}
+
+			return sliceArray(arr,1,arr.Len())
+		},
+	},
+	"slice":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2 && len(args) != 3{
+				return newError("wrong number of arguments.got =%d," +
+					"want=2 or 3", len(args))
+			}
+
+			//字符串按字符切片
+			var size int64
+			switch coll := args[0].(type) {
+			case *Array:
+				size = int64(coll.Len())
+			case *StringObject:
+				size = int64(utf8.RuneCountInString(coll.Value))
+			default:
+				return wrongArgumentType("slice",0,ARRAY_OBJ + " or " + STRING_OBJ,args[0])
+			}
+
+			start,ok := args[1].(*Integer)
+			if !ok{
+				return wrongArgumentType("slice",1,INTEGER_OBJ,args[1])
+			}
+
+			end := size
+			if len(args) == 3{
+				endObj,ok := args[2].(*Integer)
+				if !ok{
+					return wrongArgumentType("slice",2,INTEGER_OBJ,args[2])
+				}
+				end = endObj.Value
+			}
+
+			if start.Value < 0 || end > size || start.Value > end{
+				return newError("slice: bounds [%d:%d] out of range",start.Value,end)
+			}
+
+			if s,ok := args[0].(*StringObject);ok{
+				return &StringObject{Value:string([]rune(s.Value)[start.Value:end])}
+			}
+			return sliceArray(args[0].(*Array),int(start.Value),int(end))
+		},
+	},
+	"concat":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			result := NewArray(nil)
+
+			for i,arg := range args{
+				arr,ok := arg.(*Array)
+				if !ok{
+					return wrongArgumentType("concat",i,ARRAY_OBJ,arg)
+				}
+
+				//第一个数组直接共享，后面的逐个追加
+				if i == 0{
+					result = arr.Copy()
+					result.Frozen = false
+					continue
+				}
+				result.Push(arr.Elements()...)
+			}
+
+			return result
+		},
+	},
+	"reverse":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			arr,err := oneArrayArg("reverse",args)
+			if err != nil{
+				return err
+			}
+
+			elements := arr.Elements()
+			for i,j := 0,len(elements)-1; i < j; i,j = i+1,j-1{
+				elements[i],elements[j] = elements[j],elements[i]
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"sort":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1 && len(args) != 2{
+				return newError("wrong number of arguments.got =%d," +
+					"want=1 or 2", len(args))
+			}
+
+			arr,ok := args[0].(*Array)
+			if !ok{
+				return wrongArgumentType("sort",0,ARRAY_OBJ,args[0])
+			}
+
+			elements := arr.Elements()
+
+			var less func(a,b Object)Object
+			if len(args) == 2{
+				less = comparatorLess(env,args[1])
+			}else{
+				less = defaultLess
+			}
+
+			//比较出错时记录第一个错误，排序结束后返回
+			var sortErr Object
+			sort.SliceStable(elements, func(i, j int) bool {
+				if sortErr != nil{
+					return false
+				}
+
+				result := less(elements[i],elements[j])
+				if isError(result){
+					sortErr = result
+					return false
+				}
+
+				return isTurthy(result)
+			})
+
+			if sortErr != nil{
+				return sortErr
+			}
+
+			return NewArray(elements)
+		},
+	},
+}
+
+func init(){
+	for name,fn := range arrayBuiltins{
+		builtins[name] = fn
+	}
+}
+
+func oneArrayArg(name string,args []Object)(*Array,Object){
+	if len(args) != 1{
+		return nil,wrongArgumentCount(len(args),1)
+	}
+
+	arr,ok := args[0].(*Array)
+	if !ok{
+		return nil,wrongArgumentType(name,0,ARRAY_OBJ,args[0])
+	}
+
+	return arr,nil
+}
+
+//只访问[start,end)中的元素
+func sliceArray(arr *Array,start,end int)*Array{
+	elements := make([]Object,0,end-start)
+	for i := start;i < end;i++{
+		elements = append(elements,arr.At(i))
+	}
+
+	return NewArray(elements)
+}
+
+//比较函数返回true表示a应排在b前面
+func comparatorLess(env *Environment,fn Object)func(a,b Object)Object{
+	return func(a, b Object) Object {
+		return applyFunction(fn,[]Object{a,b},env)
+	}
+}
+
+//默认只支持同类型的整数或字符串比较
+func defaultLess(a,b Object)Object{
+	switch {
+	case a.Type() == INTEGER_OBJ && b.Type() == INTEGER_OBJ:
+		return nativeBoolToBooleanObj(a.(*Integer).Value < b.(*Integer).Value)
+	case a.Type() == STRING_OBJ && b.Type() == STRING_OBJ:
+		return nativeBoolToBooleanObj(a.(*StringObject).Value < b.(*StringObject).Value)
+	default:
+		return newError("sort: cannot compare %s with %s",a.Type(),b.Type())
+	}
+}
+
+func isError(obj Object)bool{
+	if obj != nil{
+		return obj.Type() == ERROR_OBJ
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package evaluator
+
+//通道缓冲的上限
+const maxChannelSize = 1 << 20
+
+//通道，spawn的任务之间通过它传递值
+var chanBuiltins = map[string]*Builtin{
+	//chan()没有缓冲，chan(n)可以缓冲n个值
+	"chan":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) > 1{
+				return newError("wrong number of arguments.got =%d," +
+					"want=0 or 1", len(args))
+			}
+
+			size := int64(0)
+			if len(args) == 1{
+				n,ok := args[0].(*Integer)
+				if !ok{
+					return wrongArgumentType("chan",0,INTEGER_OBJ,args[0])
+				}
+				if n.Value < 0{
+					return newError("chan: size cannot be negative,got %d",n.Value)
+				}
+				if n.Value > maxChannelSize{
+					return newError("chan: size cannot be larger than %d,got %d",maxChannelSize,n.Value)
+				}
+				size = n.Value
+			}
+
+			return NewChannel(int(size))
+		},
+	},
+	//发送冻结的拷贝，没有接收方或者缓冲满了时等待
+	"send":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			c,ok := args[0].(*Channel)
+			if !ok{
+				return wrongArgumentType("send",0,CHANNEL_OBJ,args[0])
+			}
+
+			return c.Send(args[1],env.inTask())
+		},
+	},
+	//等待下一个值，通道关闭并且取完之后返回null
+	"recv":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			c,ok := args[0].(*Channel)
+			if !ok{
+				return wrongArgumentType("recv",0,CHANNEL_OBJ,args[0])
+			}
+
+			value,ok := c.Recv(env.inTask())
+			if !ok{
+				return NULL
+			}
+			return value
+		},
+	},
+}
+
+func init(){
+	for name,fn := range chanBuiltins{
+		builtins[name] = fn
+	}
+}
@@ -0,0 +1,288 @@
+package evaluator
+
+//高阶函数，通过applyFunction回调用户函数
+//数组、集合和字符串的回调参数为(元素)，hash的回调参数为(key,value)
+var functionalBuiltins = map[string]*Builtin{
+	"map":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			if hash,ok := args[0].(*Hash);ok{
+				mapped := NewHash()
+				for _,pair := range hash.Pairs(){
+					value := applyFunction(args[1],[]Object{pair.Key,pair.Value},env)
+					if isError(value){
+						return value
+					}
+					mapped.Set(pair.Key.(Hashable),value)
+				}
+
+				return mapped
+			}
+
+			elements := []Object{}
+			err := eachElement("map",env,args[0], func(callArgs []Object) Object {
+				value := applyFunction(args[1],callArgs,env)
+				if isError(value){
+					return value
+				}
+				elements = append(elements,value)
+
+				return nil
+			})
+			if err != nil{
+				return err
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"filter":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			switch coll := args[0].(type) {
+			case *Hash:
+				filtered := NewHash()
+				for _,pair := range coll.Pairs(){
+					keep := applyFunction(args[1],[]Object{pair.Key,pair.Value},env)
+					if isError(keep){
+						return keep
+					}
+					if isTurthy(keep){
+						filtered.Set(pair.Key.(Hashable),pair.Value)
+					}
+				}
+
+				return filtered
+
+			case *Set:
+				filtered := NewSet()
+				for _,e := range coll.Elements(){
+					keep := applyFunction(args[1],[]Object{e},env)
+					if isError(keep){
+						return keep
+					}
+					if isTurthy(keep){
+						filtered.Add(e.(Hashable))
+					}
+				}
+
+				return filtered
+
+			case *StringObject:
+				var out []rune
+				for _,r := range coll.Value{
+					keep := applyFunction(args[1],[]Object{&StringObject{Value:string(r)}},env)
+					if isError(keep){
+						return keep
+					}
+					if isTurthy(keep){
+						out = append(out,r)
+					}
+				}
+
+				return &StringObject{Value:string(out)}
+			}
+
+			elements := []Object{}
+			err := eachElement("filter",env,args[0], func(callArgs []Object) Object {
+				keep := applyFunction(args[1],callArgs,env)
+				if isError(keep){
+					return keep
+				}
+				if isTurthy(keep){
+					elements = append(elements,callArgs[0])
+				}
+
+				return nil
+			})
+			if err != nil{
+				return err
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"reduce":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 3{
+				return wrongArgumentCount(len(args),3)
+			}
+
+			acc := args[2]
+			err := eachElement("reduce",env,args[0], func(callArgs []Object) Object {
+				acc = applyFunction(args[1],append([]Object{acc},callArgs...),env)
+				if isError(acc){
+					return acc
+				}
+
+				return nil
+			})
+			if err != nil{
+				return err
+			}
+
+			return acc
+		},
+	},
+	"each":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			err := eachElement("each",env,args[0], func(callArgs []Object) Object {
+				result := applyFunction(args[1],callArgs,env)
+				if isError(result){
+					return result
+				}
+
+				return nil
+			})
+			if err != nil{
+				return err
+			}
+
+			return NULL
+		},
+	},
+	"any":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			return testElements("any",env,args,true)
+		},
+	},
+	"all":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			return testElements("all",env,args,false)
+		},
+	},
+}
+
+func init(){
+	for name,fn := range functionalBuiltins{
+		builtins[name] = fn
+	}
+}
+
+//遍历集合，对每个元素调用fn，fn返回非nil时停止遍历并返回该值
+func eachElement(name string,env *Environment,coll Object,fn func(callArgs []Object)Object)Object{
+	switch coll := coll.(type) {
+	case *Array:
+		var stop Object
+		coll.Each(func(i int, e Object) bool {
+			stop = fn([]Object{e})
+			return stop == nil
+		})
+		if stop != nil{
+			return stop
+		}
+	case *Hash:
+		for _,pair := range coll.Pairs(){
+			if stop := fn([]Object{pair.Key,pair.Value});stop != nil{
+				return stop
+			}
+		}
+	case *Tuple:
+		for _,e := range coll.Element{
+			if stop := fn([]Object{e});stop != nil{
+				return stop
+			}
+		}
+	case *Set:
+		for _,e := range coll.Elements(){
+			if stop := fn([]Object{e});stop != nil{
+				return stop
+			}
+		}
+	case *Range:
+		//不生成数组，逐个计算
+		for i := int64(0); i < coll.Len(); i++{
+			if stop := fn([]Object{&Integer{Value:coll.At(i)}});stop != nil{
+				return stop
+			}
+		}
+	case *Iterator:
+		for{
+			value,ok := coll.Next()
+			if !ok{
+				break
+			}
+
+			stop := value
+			if !isError(value){
+				stop = fn([]Object{value})
+			}
+			if stop == nil{
+				continue
+			}
+			//提前结束时关闭生成器这样需要清理的迭代器，它的defer会执行；
+			//普通的迭代器不关闭，之后还可以继续取值
+			if coll.stop != nil{
+				if err := coll.Close();err != nil && !isError(stop){
+					return err
+				}
+			}
+			return stop
+		}
+	case *Channel:
+		for{
+			value,ok := coll.Recv(env.inTask())
+			if !ok{
+				break
+			}
+			if isError(value){
+				return value
+			}
+			if stop := fn([]Object{value});stop != nil{
+				return stop
+			}
+		}
+	case *StringObject:
+		//按字符遍历
+		for _,r := range coll.Value{
+			if stop := fn([]Object{&StringObject{Value:string(r)}});stop != nil{
+				return stop
+			}
+		}
+	default:
+		return newError("argument 0 to `%s` must be ARRAY,HASH,SET or STRING,got %s",
+			name, coll.Type())
+	}
+
+	return nil
+}
+
+//any在找到满足条件的元素时返回true，all在找到不满足条件的元素时返回false
+func testElements(name string,env *Environment,args []Object,want bool)Object{
+	if len(args) != 2{
+		return wrongArgumentCount(len(args),2)
+	}
+
+	found := false
+	err := eachElement(name,env,args[0], func(callArgs []Object) Object {
+		result := applyFunction(args[1],callArgs,env)
+		if isError(result){
+			return result
+		}
+		if isTurthy(result) == want{
+			found = true
+			return NULL
+		}
+
+		return nil
+	})
+	if isError(err){
+		return err
+	}
+
+	if found{
+		return nativeBoolToBooleanObj(want)
+	}
+
+	return nativeBoolToBooleanObj(!want)
+}
@@ -0,0 +1,144 @@
+package evaluator
+
+//hash标准库，delete和merge返回新的hash，不修改参数。
+//新hash与参数共享底层的HAMT，只复制修改路径上的节点
+var hashBuiltins = map[string]*Builtin{
+	"keys":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			hash,err := oneHashArg("keys",args)
+			if err != nil{
+				return err
+			}
+
+			elements := []Object{}
+			for _,pair := range hash.Pairs(){
+				elements = append(elements,pair.Key)
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"values":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			hash,err := oneHashArg("values",args)
+			if err != nil{
+				return err
+			}
+
+			elements := []Object{}
+			for _,pair := range hash.Pairs(){
+				elements = append(elements,pair.Value)
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"entries":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			hash,err := oneHashArg("entries",args)
+			if err != nil{
+				return err
+			}
+
+			elements := []Object{}
+			for _,pair := range hash.Pairs(){
+				entry := NewArray([]Object{pair.Key,pair.Value})
+				elements = append(elements,entry)
+			}
+
+			return NewArray(elements)
+		},
+	},
+	//has同时支持hash和集合
+	"has":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			key,err := asHashKey(args[1])
+			if err != nil{
+				return err
+			}
+
+			switch coll := args[0].(type) {
+			case *Hash:
+				_,ok := coll.Get(key)
+				return nativeBoolToBooleanObj(ok)
+			case *Set:
+				return nativeBoolToBooleanObj(coll.Has(key))
+			default:
+				return newError("argument 0 to `has` must be HASH or SET,got %s",
+					args[0].Type())
+			}
+		},
+	},
+	"delete":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) < 1{
+				return newError("wrong number of arguments.got =%d," +
+					"want at least 1", len(args))
+			}
+
+			hash,ok := args[0].(*Hash)
+			if !ok{
+				return wrongArgumentType("delete",0,HASH_OBJ,args[0])
+			}
+
+			deleted := hash.Copy()
+			for _,arg := range args[1:]{
+				key,err := asHashKey(arg)
+				if err != nil{
+					return err
+				}
+				deleted = deleted.Without(key)
+			}
+
+			return deleted
+		},
+	},
+	"merge":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			merged := NewHash()
+
+			//后面的hash覆盖前面的同名key，key的位置以第一次出现为准
+			for i,arg := range args{
+				hash,ok := arg.(*Hash)
+				if !ok{
+					return wrongArgumentType("merge",i,HASH_OBJ,arg)
+				}
+
+				//第一个hash直接共享，不用逐个复制
+				if i == 0{
+					merged = hash.Copy()
+					continue
+				}
+				hash.Each(func(key Hashable, value Object) bool {
+					merged = merged.With(key,value)
+					return true
+				})
+			}
+
+			return merged
+		},
+	},
+}
+
+func init(){
+	for name,fn := range hashBuiltins{
+		builtins[name] = fn
+	}
+}
+
+func oneHashArg(name string,args []Object)(*Hash,Object){
+	if len(args) != 1{
+		return nil,wrongArgumentCount(len(args),1)
+	}
+
+	hash,ok := args[0].(*Hash)
+	if !ok{
+		return nil,wrongArgumentType(name,0,HASH_OBJ,args[0])
+	}
+
+	return hash,nil
+}
@@ -0,0 +1,185 @@
+package evaluator
+
+//区间和迭代器，元素在需要时才生成
+var iterBuiltins = map[string]*Builtin{
+	//range(end)、range(start,end)或range(start,end,step)
+	"range":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) < 1 || len(args) > 3{
+				return newError("wrong number of arguments.got =%d," +
+					"want=1 to 3", len(args))
+			}
+
+			bounds := make([]int64,len(args))
+			for i,arg := range args{
+				n,ok := arg.(*Integer)
+				if !ok{
+					return wrongArgumentType("range",i,INTEGER_OBJ,arg)
+				}
+				bounds[i] = n.Value
+			}
+
+			switch len(bounds) {
+			case 1:
+				return newRange(0,bounds[0],1)
+			case 2:
+				return newRange(bounds[0],bounds[1],1)
+			default:
+				if bounds[2] == 0{
+					return newError("range: step cannot be 0")
+				}
+				return newRange(bounds[0],bounds[1],bounds[2])
+			}
+		},
+	},
+	"iter":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			return newIterator("iter",env,args[0])
+		},
+	},
+	//取得下一个值，结束后返回null
+	"next":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			it,ok := args[0].(*Iterator)
+			if !ok{
+				return wrongArgumentType("next",0,ITERATOR_OBJ,args[0])
+			}
+
+			value,ok := it.Next()
+			if !ok{
+				return NULL
+			}
+			return value
+		},
+	},
+	//提前结束迭代器，生成器中的defer会执行；也用来关闭通道
+	"close":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			var err Object
+			switch arg := args[0].(type) {
+			case *Iterator:
+				err = arg.Close()
+			case *Channel:
+				err = arg.Close()
+			default:
+				return wrongArgumentType("close",0,ITERATOR_OBJ + " or " + CHANNEL_OBJ,args[0])
+			}
+
+			if err != nil{
+				return err
+			}
+			return NULL
+		},
+	},
+	"to_array":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			it := newIterator("to_array",env,args[0])
+			if isError(it){
+				return it
+			}
+
+			elements := []Object{}
+			for{
+				value,ok := it.(*Iterator).Next()
+				if !ok{
+					break
+				}
+				if isError(value){
+					return value
+				}
+				elements = append(elements,value)
+			}
+
+			return NewArray(elements)
+		},
+	},
+}
+
+func init(){
+	for name,fn := range iterBuiltins{
+		builtins[name] = fn
+	}
+}
+
+//集合的迭代器，hash的元素是(key,value)元组
+//数组先取一个快照，迭代过程中push不影响迭代
+func newIterator(name string,env *Environment,coll Object)Object{
+	i := 0
+
+	switch coll := coll.(type) {
+	case *Iterator:
+		return coll
+	case *Channel:
+		//接收到通道关闭为止，是不是在任务中按创建迭代器的地方算
+		task := env.inTask()
+		return NewIterator(func() (Object, bool) {
+			return coll.Recv(task)
+		})
+	case *Range:
+		size := coll.Len()
+		return NewIterator(func() (Object, bool) {
+			if int64(i) >= size{
+				return nil,false
+			}
+			i++
+			return &Integer{Value:coll.At(int64(i - 1))},true
+		})
+	case *Array:
+		snapshot := coll.Copy()
+		return NewIterator(func() (Object, bool) {
+			if i >= snapshot.Len(){
+				return nil,false
+			}
+			i++
+			return snapshot.At(i - 1),true
+		})
+	case *Tuple:
+		return sliceIterator(coll.Element)
+	case *Set:
+		return sliceIterator(coll.Elements())
+	case *Hash:
+		entries := []Object{}
+		for _,pair := range coll.Pairs(){
+			entries = append(entries,&Tuple{Element:[]Object{pair.Key,pair.Value}})
+		}
+		return sliceIterator(entries)
+	case *StringObject:
+		runes := []rune(coll.Value)
+		return NewIterator(func() (Object, bool) {
+			if i >= len(runes){
+				return nil,false
+			}
+			i++
+			return &StringObject{Value:string(runes[i - 1])},true
+		})
+	default:
+		return newError("argument 0 to `%s` must be iterable,got %s",name,coll.Type())
+	}
+}
+
+func sliceIterator(elements []Object)*Iterator{
+	i := 0
+	return NewIterator(func() (Object, bool) {
+		if i >= len(elements){
+			return nil,false
+		}
+		i++
+		return elements[i - 1],true
+	})
+}
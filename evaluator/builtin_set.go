@@ -0,0 +1,147 @@
+package evaluator
+
+//集合运算，都返回新的集合
+var setBuiltins = map[string]*Builtin{
+	"set":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) > 1{
+				return newError("wrong number of arguments.got =%d," +
+					"want=0 or 1", len(args))
+			}
+
+			set := NewSet()
+			if len(args) == 0{
+				return set
+			}
+
+			if _,ok := args[0].(*Hash);ok{
+				return wrongArgumentType("set",0,ARRAY_OBJ,args[0])
+			}
+
+			err := eachElement("set",env,args[0], func(callArgs []Object) Object {
+				member,err := asHashKey(callArgs[0])
+				if err != nil{
+					return err
+				}
+				set.Add(member)
+
+				return nil
+			})
+			if err != nil{
+				return err
+			}
+
+			return set
+		},
+	},
+	"union":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			sets,err := setArgs("union",args)
+			if err != nil{
+				return err
+			}
+
+			result := NewSet()
+			for _,s := range sets{
+				for _,e := range s.Elements(){
+					result.Add(e.(Hashable))
+				}
+			}
+
+			return result
+		},
+	},
+	"intersection":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			sets,err := setArgs("intersection",args)
+			if err != nil{
+				return err
+			}
+
+			result := NewSet()
+			for _,e := range sets[0].Elements(){
+				inAll := true
+				for _,other := range sets[1:]{
+					if !other.Has(e.(Hashable)){
+						inAll = false
+						break
+					}
+				}
+				if inAll{
+					result.Add(e.(Hashable))
+				}
+			}
+
+			return result
+		},
+	},
+	"difference":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			sets,err := setArgs("difference",args)
+			if err != nil{
+				return err
+			}
+
+			result := NewSet()
+			for _,e := range sets[0].Elements(){
+				inOther := false
+				for _,other := range sets[1:]{
+					if other.Has(e.(Hashable)){
+						inOther = true
+						break
+					}
+				}
+				if !inOther{
+					result.Add(e.(Hashable))
+				}
+			}
+
+			return result
+		},
+	},
+	//subset(a,b)判断a是否是b的子集
+	"subset":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			sets,err := setArgs("subset",args)
+			if err != nil{
+				return err
+			}
+
+			for _,e := range sets[0].Elements(){
+				if !sets[1].Has(e.(Hashable)){
+					return FALSE
+				}
+			}
+
+			return TRUE
+		},
+	},
+}
+
+func init(){
+	for name,fn := range setBuiltins{
+		builtins[name] = fn
+	}
+}
+
+func setArgs(name string,args []Object)([]*Set,Object){
+	if len(args) < 2{
+		return nil,newError("wrong number of arguments.got =%d," +
+			"want at least 2", len(args))
+	}
+
+	sets := make([]*Set,len(args))
+	for i,arg := range args{
+		s,ok := arg.(*Set)
+		if !ok{
+			return nil,wrongArgumentType(name,i,SET_OBJ,arg)
+		}
+		sets[i] = s
+	}
+
+	return sets,nil
+}
@@ -0,0 +1,364 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+//repeat生成的字符串的最大字节数
+const maxStringSize = 1 << 30
+
+//字符串标准库，基于go的strings包
+//长度和位置都按字符(unicode码点)计算，bytes()取得utf8编码
+var stringBuiltins = map[string]*Builtin{
+	"split":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("split",0,STRING_OBJ,args[0])
+			}
+			sep,ok := args[1].(*StringObject)
+			if !ok{
+				return wrongArgumentType("split",1,STRING_OBJ,args[1])
+			}
+
+			parts := strings.Split(s.Value,sep.Value)
+			elements := make([]Object,len(parts))
+			for i,part := range parts{
+				elements[i] = &StringObject{Value:part}
+			}
+
+			return NewArray(elements)
+		},
+	},
+	"join":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			arr,ok := args[0].(*Array)
+			if !ok{
+				return wrongArgumentType("join",0,ARRAY_OBJ,args[0])
+			}
+			sep,ok := args[1].(*StringObject)
+			if !ok{
+				return wrongArgumentType("join",1,STRING_OBJ,args[1])
+			}
+
+			parts := make([]string,arr.Len())
+			for i,e := range arr.Elements(){
+				str,ok := e.(*StringObject)
+				if !ok{
+					return newError("join: element %d must be %s,got %s",i,STRING_OBJ,e.Type())
+				}
+				parts[i] = str.Value
+			}
+
+			return &StringObject{Value:strings.Join(parts,sep.Value)}
+		},
+	},
+	"trim":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1 && len(args) != 2{
+				return newError("wrong number of arguments.got =%d," +
+					"want=1 or 2", len(args))
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("trim",0,STRING_OBJ,args[0])
+			}
+
+			if len(args) == 1{
+				return &StringObject{Value:strings.TrimSpace(s.Value)}
+			}
+
+			cutset,ok := args[1].(*StringObject)
+			if !ok{
+				return wrongArgumentType("trim",1,STRING_OBJ,args[1])
+			}
+
+			return &StringObject{Value:strings.Trim(s.Value,cutset.Value)}
+		},
+	},
+	"upper":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("upper",0,STRING_OBJ,args[0])
+			}
+
+			return &StringObject{Value:strings.ToUpper(s.Value)}
+		},
+	},
+	"lower":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("lower",0,STRING_OBJ,args[0])
+			}
+
+			return &StringObject{Value:strings.ToLower(s.Value)}
+		},
+	},
+	"contains":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			s,sub,err := twoStringArgs("contains",args)
+			if err != nil{
+				return err
+			}
+
+			return nativeBoolToBooleanObj(strings.Contains(s,sub))
+		},
+	},
+	"index_of":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			s,sub,err := twoStringArgs("index_of",args)
+			if err != nil{
+				return err
+			}
+
+			//返回字符的位置
+			i := strings.Index(s,sub)
+			if i < 0{
+				return &Integer{Value:-1}
+			}
+			return &Integer{Value:int64(utf8.RuneCountInString(s[:i]))}
+		},
+	},
+	"replace":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 3{
+				return wrongArgumentCount(len(args),3)
+			}
+
+			for i,arg := range args{
+				if arg.Type() != STRING_OBJ{
+					return wrongArgumentType("replace",i,STRING_OBJ,arg)
+				}
+			}
+
+			s := args[0].(*StringObject).Value
+			old := args[1].(*StringObject).Value
+			new := args[2].(*StringObject).Value
+
+			return &StringObject{Value:strings.ReplaceAll(s,old,new)}
+		},
+	},
+	"starts_with":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			s,prefix,err := twoStringArgs("starts_with",args)
+			if err != nil{
+				return err
+			}
+
+			return nativeBoolToBooleanObj(strings.HasPrefix(s,prefix))
+		},
+	},
+	"ends_with":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			s,suffix,err := twoStringArgs("ends_with",args)
+			if err != nil{
+				return err
+			}
+
+			return nativeBoolToBooleanObj(strings.HasSuffix(s,suffix))
+		},
+	},
+	"repeat":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2{
+				return wrongArgumentCount(len(args),2)
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("repeat",0,STRING_OBJ,args[0])
+			}
+			count,ok := args[1].(*Integer)
+			if !ok{
+				return wrongArgumentType("repeat",1,INTEGER_OBJ,args[1])
+			}
+			if count.Value < 0{
+				return newError("repeat: negative count %d",count.Value)
+			}
+			//先用除法比较，乘法可能溢出
+			if len(s.Value) > 0 && count.Value > maxStringSize / int64(len(s.Value)){
+				return newError("repeat: result longer than %d bytes",maxStringSize)
+			}
+
+			return &StringObject{Value:strings.Repeat(s.Value,int(count.Value))}
+		},
+	},
+	"substr":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 2 && len(args) != 3{
+				return newError("wrong number of arguments.got =%d," +
+					"want=2 or 3", len(args))
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("substr",0,STRING_OBJ,args[0])
+			}
+			start,ok := args[1].(*Integer)
+			if !ok{
+				return wrongArgumentType("substr",1,INTEGER_OBJ,args[1])
+			}
+
+			//按字符而不是字节计算位置
+			runes := []rune(s.Value)
+			size := int64(len(runes))
+			if start.Value < 0 || start.Value > size{
+				return newError("substr: start %d out of range",start.Value)
+			}
+
+			end := size
+			if len(args) == 3{
+				length,ok := args[2].(*Integer)
+				if !ok{
+					return wrongArgumentType("substr",2,INTEGER_OBJ,args[2])
+				}
+				if length.Value < 0{
+					return newError("substr: negative length %d",length.Value)
+				}
+				//和剩下的长度比较，start+length可能溢出
+				if length.Value < size - start.Value{
+					end = start.Value + length.Value
+				}
+			}
+
+			return &StringObject{Value:string(runes[start.Value:end])}
+		},
+	},
+	//字符串的utf8编码，每个字节是一个整数
+	"bytes":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) != 1{
+				return wrongArgumentCount(len(args),1)
+			}
+
+			s,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("bytes",0,STRING_OBJ,args[0])
+			}
+
+			elements := make([]Object,len(s.Value))
+			for i := 0; i < len(s.Value); i++{
+				elements[i] = &Integer{Value:int64(s.Value[i])}
+			}
+
+			return NewArray(elements)
+		},
+	},
+	//format("%s has %d items",name,n)，格式和go的fmt.Sprintf一样：
+	//%s和%v可以用于任何值，输出Inspect的结果；整数可以用%d、%x、%o、%b、%c，
+	//字符串可以用%q、%x，布尔值可以用%t。值和动词不匹配、参数多了或少了时
+	//按fmt的规则输出%!d(string=s)这样的标记，不是错误。不支持%[1]d这样的参数索引
+	"format":&Builtin{
+		Fn: func(env *Environment,args ...Object) Object {
+			if len(args) < 1{
+				return newError("wrong number of arguments.got =%d," +
+					"want at least 1", len(args))
+			}
+
+			f,ok := args[0].(*StringObject)
+			if !ok{
+				return wrongArgumentType("format",0,STRING_OBJ,args[0])
+			}
+
+			verbs := formatVerbs(f.Value)
+			values := make([]interface{},len(args) - 1)
+			for i,arg := range args[1:]{
+				if i < len(verbs) && (verbs[i] == 's' || verbs[i] == 'v'){
+					values[i] = arg.Inspect()
+				}else{
+					values[i] = nativeValue(arg)
+				}
+			}
+
+			return &StringObject{Value:fmt.Sprintf(f.Value,values...)}
+		},
+	},
+}
+
+func init(){
+	for name,fn := range stringBuiltins{
+		builtins[name] = fn
+	}
+	builtins["sprintf"] = stringBuiltins["format"]
+}
+
+//格式中依次使用参数的动词，%%不使用参数，宽度或精度是*时也使用一个参数
+func formatVerbs(format string)[]rune{
+	verbs := []rune{}
+	for i := 0;i < len(format);i++{
+		if format[i] != '%'{
+			continue
+		}
+
+		for i++;i < len(format) && strings.IndexByte("+-# 0123456789.*",format[i]) >= 0;i++{
+			if format[i] == '*'{
+				verbs = append(verbs,'*')
+			}
+		}
+		if i >= len(format){
+			break
+		}
+
+		verb,size := utf8.DecodeRuneInString(format[i:])
+		if verb != '%'{
+			verbs = append(verbs,verb)
+		}
+		i += size - 1
+	}
+
+	return verbs
+}
+
+//整数、字符串和布尔值转成go的值，%d、%x、%q等才能使用
+//其他值用Inspect的结果
+func nativeValue(obj Object)interface{}{
+	switch obj := obj.(type) {
+	case *Integer:
+		return obj.Value
+	case *StringObject:
+		return obj.Value
+	case *Boolean:
+		return obj.Value
+	default:
+		return obj.Inspect()
+	}
+}
+
+//检查两个字符串参数
+func twoStringArgs(name string,args []Object)(string,string,Object){
+	if len(args) != 2{
+		return "","",wrongArgumentCount(len(args),2)
+	}
+
+	s,ok := args[0].(*StringObject)
+	if !ok{
+		return "","",wrongArgumentType(name,0,STRING_OBJ,args[0])
+	}
+	other,ok := args[1].(*StringObject)
+	if !ok{
+		return "","",wrongArgumentType(name,1,STRING_OBJ,args[1])
+	}
+
+	return s.Value,other.Value,nil
+}
@@ -0,0 +1,372 @@
+package evaluator
+
+import (
+	"ast"
+	"math/rand"
+	"sync"
+)
+
+//spawn f(x)：在当前goroutine中求出函数和参数，调用放到新的goroutine中
+//spawn g：g是没有参数的函数
+//值是缓冲为1的通道，调用的结果(包括错误)发送到通道后关闭
+func evalSpawnExpression(node *ast.SpawnExpression,env *Environment)Object{
+	var fn Object
+	args := []Object{}
+
+	if call,ok := node.Call.(*ast.CallExpression);ok{
+		if fn = Eval(call.Function,env);isError(fn){
+			return fn
+		}
+		args = evalExpression(call.Arguments,env)
+		for _,arg := range args{
+			if isError(arg){
+				return arg
+			}
+		}
+	}else if fn = Eval(node.Call,env);isError(fn){
+		return fn
+	}
+
+	switch fn.(type) {
+	case *Function,*Builtin:
+	default:
+		return newError("cannot spawn %s",fn.Type())
+	}
+
+	fn,err := shareValue(fn)
+	if err != nil{
+		return err
+	}
+	for i,arg := range args{
+		if args[i],err = shareValue(arg);err != nil{
+			return err
+		}
+	}
+
+	//任务中的调用都从这个环境开始，调用链上的函数由此知道自己在任务中
+	taskEnv := NewEnclosedEnvironment(env)
+	taskEnv.frame = &callFrame{task:true}
+
+	result := NewChannel(1)
+	startTask()
+	go func() {
+		value := applyFunction(fn,args,taskEnv)
+		if value == nil{
+			value = NULL
+		}
+		//结果不能交给调用方时，调用方得到错误
+		if err := result.Send(value,true);isError(err){
+			result.Send(err,true)
+		}
+		result.Close()
+		endTask()
+	}()
+
+	return result
+}
+
+//交给其他任务的值：可变的值换成冻结的拷贝，函数换成捕获的值都冻结了的拷贝，
+//任务和创建它的代码不会同时修改同一个值；其中有迭代器时返回错误
+func shareValue(obj Object)(Object,Object){
+	f := &freezer{seen:map[Object]Object{},functions:true}
+	shared := f.freeze(obj)
+	if f.err != nil{
+		return nil,f.err
+	}
+
+	return shared,nil
+}
+
+//函数体中用到的外层的名字，在拷贝自己的环境中绑定成共享的值；
+//其中的函数同样处理，所以通过辅助函数间接修改外层的值也不行
+func (f *freezer)shareFunction(function *Function)Object{
+	shared := NewEnclosedEnvironment(function.Env)
+	//任务在自己的goroutine中import，要对加载器加锁
+	shared.importing = false
+
+	task := *function
+	task.Env = shared
+	f.seen[function] = &task
+
+	ast.Walk(function.Body, func(node ast.Node) bool {
+		if ident,ok := node.(*ast.Indetifier);ok{
+			if value,ok := function.Env.Get(ident.Value);ok{
+				if frozen := f.freeze(value);frozen != value{
+					shared.Set(ident.Value,frozen)
+				}
+			}
+		}
+		return true
+	})
+
+	return &task
+}
+
+//先按顺序求出所有分支的通道和要发送的值，然后等待其中一个分支可以执行，
+//都不能执行时有默认分支就执行默认分支
+func evalSelectExpression(node *ast.SelectExpression,env *Environment)Object{
+	ops := []channelOp{}
+	owners := []*ast.SelectCase{} //每个操作对应的select分支
+	var fallback *ast.SelectCase
+
+	for _,sc := range node.Cases{
+		if sc.Channel == nil{
+			fallback = sc
+			continue
+		}
+
+		obj := Eval(sc.Channel,env)
+		if isError(obj){
+			return obj
+		}
+		c,ok := obj.(*Channel)
+		if !ok{
+			return newError("select case must be CHANNEL,got %s",obj.Type())
+		}
+
+		op := channelOp{c:c}
+		if sc.Value != nil{
+			value := Eval(sc.Value,env)
+			if isError(value){
+				return value
+			}
+			shared,err := shareValue(value)
+			if err != nil{
+				return err
+			}
+			op = channelOp{c:c,send:true,value:shared}
+		}
+
+		ops = append(ops,op)
+		owners = append(owners,sc)
+	}
+
+	result := selectChannels("select",ops,fallback == nil,env.inTask())
+	if result.err != nil{
+		return result.err
+	}
+	if result.index < 0{
+		return evalSelectCase(fallback,NULL,env)
+	}
+
+	//通道关闭后recv分支得到null
+	var value Object = NULL
+	if result.ok{
+		value = result.value
+	}
+
+	return evalSelectCase(owners[result.index],value,env)
+}
+
+//接收到的值绑定在分支自己的环境中
+func evalSelectCase(sc *ast.SelectCase,value Object,env *Environment)Object{
+	caseEnv := NewEnclosedEnvironment(env)
+	if sc.Name != nil{
+		caseEnv.Set(sc.Name.Value,value)
+	}
+
+	result := Eval(sc.Body,caseEnv)
+	if result == nil{
+		return NULL
+	}
+	return result
+}
+
+//通道的调度：所有通道共用一把锁，发送和接收在锁中直接交接。
+//running是没有在等待通道的任务数，都在等待时不会再有任务让通道就绪：
+//任务就一直等待；不在任务中的代码(主程序)得到死锁错误而不是永远阻塞
+var sched struct {
+	mu sync.Mutex
+	running int
+	roots []*channelWaiter //正在等待的主程序
+}
+
+func startTask(){
+	sched.mu.Lock()
+	sched.running++
+	sched.mu.Unlock()
+}
+
+func endTask(){
+	sched.mu.Lock()
+	sched.running--
+	checkDeadlock()
+	sched.mu.Unlock()
+}
+
+//持有sched.mu时调用
+func checkDeadlock(){
+	if sched.running > 0{
+		return
+	}
+
+	for _,w := range sched.roots{
+		if !w.fired{
+			w.fired = true
+			w.result = channelResult{err:newError("deadlock: %s would block forever",w.name)}
+			w.wake <- struct{}{}
+		}
+	}
+	sched.roots = nil
+}
+
+//select中的一个操作，send为false时是接收
+type channelOp struct {
+	c *Channel
+	send bool
+	value Object
+}
+
+//index是完成的操作，没有操作可以完成并且不等待时是-1
+type channelResult struct {
+	index int
+	value Object
+	ok bool //接收到了值，false表示通道已经关闭
+	err Object
+}
+
+//一次等待，同时排在每个操作的通道队列中，第一个完成的操作唤醒它
+type channelWaiter struct {
+	name string
+	task bool
+	fired bool
+	wake chan struct{}
+	result channelResult
+}
+
+//通道队列中的一项，index是操作在select中的位置
+type channelWait struct {
+	*channelWaiter
+	index int
+	value Object //要发送的值
+}
+
+//完成等待中的操作；被唤醒的任务由唤醒方计入running，
+//这样任务醒来之前不会被当成死锁
+func (w *channelWait)fire(result channelResult){
+	w.fired = true
+	w.result = result
+	if w.task{
+		sched.running++
+	}
+	w.wake <- struct{}{}
+}
+
+//按随机的顺序尝试每个操作，都不能完成时block为true就等待
+func selectChannels(name string,ops []channelOp,block bool,task bool)channelResult{
+	sched.mu.Lock()
+
+	for _,i := range rand.Perm(len(ops)){
+		if result,ok := ops[i].try(i);ok{
+			sched.mu.Unlock()
+			return result
+		}
+	}
+
+	if !block{
+		sched.mu.Unlock()
+		return channelResult{index:-1}
+	}
+	if !task && sched.running == 0{
+		sched.mu.Unlock()
+		return channelResult{err:newError("deadlock: %s would block forever",name)}
+	}
+
+	w := &channelWaiter{name:name,task:task,wake:make(chan struct{},1)}
+	for i,op := range ops{
+		wait := &channelWait{channelWaiter:w,index:i,value:op.value}
+		if op.send{
+			op.c.sendq = append(op.c.sendq,wait)
+		}else{
+			op.c.recvq = append(op.c.recvq,wait)
+		}
+	}
+	if task{
+		sched.running--
+		checkDeadlock()
+	}else{
+		sched.roots = append(sched.roots,w)
+	}
+	sched.mu.Unlock()
+
+	<-w.wake
+
+	//从其他通道的队列中去掉这次等待
+	sched.mu.Lock()
+	for _,op := range ops{
+		op.c.recvq = withoutWaiter(op.c.recvq,w)
+		op.c.sendq = withoutWaiter(op.c.sendq,w)
+	}
+	for i,root := range sched.roots{
+		if root == w{
+			sched.roots = append(sched.roots[:i],sched.roots[i+1:]...)
+			break
+		}
+	}
+	sched.mu.Unlock()
+
+	return w.result
+}
+
+//持有sched.mu时调用，能立即完成时返回true
+func (op channelOp)try(index int)(channelResult,bool){
+	c := op.c
+
+	if op.send{
+		if c.closed{
+			return channelResult{index:index,err:newError("send on closed channel")},true
+		}
+		if r := popWait(&c.recvq);r != nil{
+			r.fire(channelResult{index:r.index,value:op.value,ok:true})
+			return channelResult{index:index},true
+		}
+		if len(c.buffer) < c.size{
+			c.buffer = append(c.buffer,op.value)
+			return channelResult{index:index},true
+		}
+		return channelResult{},false
+	}
+
+	if len(c.buffer) > 0{
+		value := c.buffer[0]
+		c.buffer = c.buffer[1:]
+		//缓冲空出一个位置，等待中的发送放进缓冲
+		if s := popWait(&c.sendq);s != nil{
+			c.buffer = append(c.buffer,s.value)
+			s.fire(channelResult{index:s.index})
+		}
+		return channelResult{index:index,value:value,ok:true},true
+	}
+	if s := popWait(&c.sendq);s != nil{
+		s.fire(channelResult{index:s.index})
+		return channelResult{index:index,value:s.value,ok:true},true
+	}
+	if c.closed{
+		return channelResult{index:index},true
+	}
+	return channelResult{},false
+}
+
+//取出队列中第一个还在等待的项，已经被其他操作唤醒的跳过
+func popWait(queue *[]*channelWait)*channelWait{
+	for len(*queue) > 0{
+		w := (*queue)[0]
+		*queue = (*queue)[1:]
+		if !w.fired{
+			return w
+		}
+	}
+
+	return nil
+}
+
+func withoutWaiter(queue []*channelWait,w *channelWaiter)[]*channelWait{
+	kept := queue[:0]
+	for _,wait := range queue{
+		if wait.channelWaiter != w{
+			kept = append(kept,wait)
+		}
+	}
+
+	return kept
+}
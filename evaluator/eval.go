@@ -2,7 +2,10 @@ package evaluator
 
 import (
 	"ast"
+	"bytes"
 	"fmt"
+	"math"
+	"unicode/utf8"
 	)
 
 func Eval(node ast.Node,env *Environment) Object {
@@ -12,23 +15,79 @@ func Eval(node ast.Node,env *Environment) Object {
 
 	case *ast.IndexExpression:
 		left := Eval(node.Left,env)
+		if isError(left){
+			return left
+		}
 		index := Eval(node.Index,env)
+		if isError(index){
+			return index
+		}
 		return evalIndexExpression(left,index)
 
+	case *ast.SliceExpression:
+		return evalSliceExpression(node,env)
+
+	case *ast.RangeExpression:
+		return evalRangeExpression(node,env)
+
+	case *ast.YieldExpression:
+		return evalYieldExpression(node,env)
+
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(node,env)
+
+	case *ast.SelectExpression:
+		return evalSelectExpression(node,env)
+
+	case *ast.DeferStatement:
+		return evalDeferStatement(node,env)
+
+	case *ast.StructStatement:
+		fields := []string{}
+		for _,f := range node.Fields{
+			fields = append(fields,f.Value)
+		}
+		env.Set(node.Name.Value,&StructType{Name:node.Name.Value,Fields:fields})
+
+	case *ast.StructLiteral:
+		return evalStructLiteral(node,env)
+
+	case *ast.EnumStatement:
+		env.Set(node.Name.Value,newEnumType(node))
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node,env)
+
+	case *ast.FieldExpression:
+		left := Eval(node.Left,env)
+		if isError(left){
+			return left
+		}
+		return evalFieldExpression(left,node.Field.Value)
+
+	case *ast.AssignExpression:
+		return evalAssignExpression(node,env)
+
+	case *ast.MethodCallExpression:
+		return evalMethodCall(node,env)
+
+	case *ast.SetLiteral:
+		return evalSetLiteral(node,env)
+
+	case *ast.TupleLiteral:
+		return &Tuple{Element:evalExpression(node.Element,env)}
+
 	case *ast.ArrayLiteral:
 
-		array := &Array{}
 		o := evalExpression(node.Element,env)
 
-		array.Element = o
-
-		return array
+		return NewArray(o)
 
 	case *ast.CallExpression:
 		function := Eval(node.Function,env) //get function object
 		args := evalExpression(node.Arguments,env)
 
-		return applyFunction(function,args)
+		return applyFunction(function,args,env)
 
 	case *ast.FunctionLiteral:
 		params := node.Parameters
@@ -36,14 +95,20 @@ func Eval(node ast.Node,env *Environment) Object {
 
 		return &Function{Parameter:params,
 		Body:body,
-		Env:env}
+		Env:env,
+		Generator:node.IsGenerator}
 
 	case *ast.Indetifier:
 		return evalIdentifier(node,env)
 
 	case *ast.LetStatement:
-		val := Eval(node.Value,env)
-		env.Set(node.Name.TokenLiteral(),val)
+		return evalLetStatement(node,env)
+
+	case *ast.ExportStatement:
+		return evalExportStatement(node,env)
+
+	case *ast.ImportExpression:
+		return evalImportExpression(node,env)
 
 	case *ast.Program:
 		return evalStatements(node.Statements,env)
@@ -57,6 +122,9 @@ func Eval(node ast.Node,env *Environment) Object {
 	case *ast.StringLiteral:
 		return &StringObject{Value:node.Value}
 
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node,env)
+
 	case *ast.Boolean:
 		return nativeBoolToBooleanObj(node.Value)
 
@@ -86,24 +154,219 @@ func Eval(node ast.Node,env *Environment) Object {
 	return nil
 }
 
+func evalLetStatement(node *ast.LetStatement,env *Environment)Object{
+	for _,name := range node.Names(){
+		if env.IsConst(name.Value){
+			return newError("cannot rebind const %s",name.Value)
+		}
+	}
+
+	val := Eval(node.Value,env)
+	if isError(val){
+		return val
+	}
+	if node.IsConst(){
+		val = deepFreeze(val)
+	}
+
+	if node.Pattern == nil{
+		bind(env,node.Name.Value,val,node.IsConst())
+		return nil
+	}
+
+	//先绑定到临时环境，整个模式匹配成功后才写入
+	scratch := NewEnclosedEnvironment(env)
+	if err := evalLetPattern(node.Pattern,val,scratch);err != nil{
+		return err
+	}
+	for _,name := range node.Names(){
+		bind(env,name.Value,scratch.store[name.Value],node.IsConst())
+	}
+
+	return nil
+}
+
+func bind(env *Environment,name string,val Object,isConst bool){
+	if isConst{
+		env.SetConst(name,val)
+	}else{
+		env.Set(name,val)
+	}
+}
+
+//字符串直接拼接，其他值用Inspect的结果
+func evalInterpolatedString(node *ast.InterpolatedString,env *Environment)Object{
+	var out bytes.Buffer
+
+	for _,part := range node.Parts{
+		value := Eval(part,env)
+		if isError(value){
+			return value
+		}
+
+		if s,ok := value.(*StringObject);ok{
+			out.WriteString(s.Value)
+			continue
+		}
+		out.WriteString(value.Inspect())
+	}
+
+	return &StringObject{Value:out.String()}
+}
+
 func evalHashLiteral(node *ast.HashLiteral,env *Environment)Object{
-	pairs := make(map[HashKey]HashPair)
+	hash := NewHash()
+
+	//按源码顺序求值，保证副作用的顺序是确定的
+	for _,pair := range node.Pairs{
+		key := Eval(pair.Key,env)
+
+		hashKey,err := asHashKey(key)
+		if err != nil{
+			return err
+		}
+
+		value := Eval(pair.Value,env)
+
+		hash.Set(hashKey,value)
+	}
+
+	return hash
+}
+
+func evalStructLiteral(node *ast.StructLiteral,env *Environment)Object{
+	def,ok := Eval(node.Name,env).(*StructType)
+	if !ok{
+		return newError("%s is not a struct",node.Name.Value)
+	}
+
+	values := make([]Object,len(def.Fields))
+	for _,f := range node.Fields{
+		idx := def.fieldIndex(f.Name.Value)
+		if idx < 0{
+			return newError("struct %s has no field %s",def.Name,f.Name.Value)
+		}
+		if values[idx] != nil{
+			return newError("duplicate field %s in %s literal",f.Name.Value,def.Name)
+		}
+
+		value := Eval(f.Value,env)
+		if isError(value){
+			return value
+		}
+		values[idx] = value
+	}
+
+	for i,v := range values{
+		if v == nil{
+			return newError("missing field %s in %s literal",def.Fields[i],def.Name)
+		}
+	}
+
+	return &Struct{Def:def,Values:values}
+}
+
+//按位置构造，Point(1,2)
+func newStruct(def *StructType,args []Object)Object{
+	if len(args) != len(def.Fields){
+		return newError("wrong number of arguments.got =%d," +
+			"want=%d", len(args), len(def.Fields))
+	}
+
+	values := make([]Object,len(args))
+	copy(values,args)
 
-	for knode,vnode := range node.Pairs{
-		key := Eval(knode,env)
+	return &Struct{Def:def,Values:values}
+}
 
-		hashKey,ok := key.(Hashable)
+func evalFieldExpression(left Object,field string)Object{
+	switch left := left.(type) {
+	case *Struct:
+		value,ok := left.Get(field)
+		if !ok{
+			return newError("struct %s has no field %s",left.Def.Name,field)
+		}
+		return value
+	case *Hash:
+		//h.name等价于h["name"]
+		value,ok := left.Get(&StringObject{Value:field})
 		if !ok{
-			return newError("invalid hash key")
+			return NULL
+		}
+		return value
+	case *EnumType:
+		variant,ok := left.Variant(field)
+		if !ok{
+			return newError("enum %s has no variant %s",left.Name,field)
+		}
+		//没有字段的分支直接就是值
+		if len(variant.Fields) == 0{
+			return &EnumValue{Variant:variant}
 		}
+		return variant
+	case *EnumValue:
+		value,ok := left.Get(field)
+		if !ok{
+			return newError("variant %s has no field %s",left.Variant.Name,field)
+		}
+		return value
+	case *Module:
+		value,ok := left.Get(field)
+		if !ok{
+			return newError("module %s has no export %s",left.Name,field)
+		}
+		return value
+	default:
+		return newError("field access not supported on %s",left.Type())
+	}
+}
+
+func evalAssignExpression(node *ast.AssignExpression,env *Environment)Object{
+	target,ok := node.Target.(*ast.FieldExpression)
+	if !ok{
+		return newError("invalid assignment target %s",node.Target.String())
+	}
+
+	left := Eval(target.Left,env)
+	if isError(left){
+		return left
+	}
+
+	obj,ok := left.(*Struct)
+	if !ok{
+		return newError("field assignment not supported on %s",left.Type())
+	}
+	if obj.Frozen{
+		return newError("cannot assign to field %s of frozen %s",target.Field.Value,obj.Def.Name)
+	}
 
-		value := Eval(vnode,env)
+	idx := obj.Def.fieldIndex(target.Field.Value)
+	if idx < 0{
+		return newError("struct %s has no field %s",obj.Def.Name,target.Field.Value)
+	}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = HashPair{Key:key,Value:value}
+	value := Eval(node.Value,env)
+	if isError(value){
+		return value
 	}
+	obj.Values[idx] = value
 
-	return &Hash{Pairs:pairs}
+	return value
+}
+
+func evalSetLiteral(node *ast.SetLiteral,env *Environment)Object{
+	set := NewSet()
+
+	for _,e := range node.Element{
+		member,err := asHashKey(Eval(e,env))
+		if err != nil{
+			return err
+		}
+
+		set.Add(member)
+	}
+
+	return set
 }
 
 func evalIndexExpression(left,index Object)Object{
@@ -112,6 +375,18 @@ func evalIndexExpression(left,index Object)Object{
 		index.Type() == INTEGER_OBJ:
 			return evalArrayIndexExpression(left,index)
 
+	case left.Type() == TUPLE_OBJ &&
+		index.Type() == INTEGER_OBJ:
+			return evalTupleIndexExpression(left,index)
+
+	case left.Type() == STRING_OBJ &&
+		index.Type() == INTEGER_OBJ:
+			return evalStringIndexExpression(left,index)
+
+	case left.Type() == RANGE_OBJ &&
+		index.Type() == INTEGER_OBJ:
+			return evalRangeIndexExpression(left,index)
+
 	case left.Type() == HASH_OBJ:
 		return evalHashIndexExpression(left,index)
 	default:
@@ -122,29 +397,175 @@ func evalIndexExpression(left,index Object)Object{
 func evalHashIndexExpression(left Object,index Object)Object{
 	hashObj := left.(*Hash)
 
-	key,ok := index.(Hashable)
-	if !ok{
-		return newError("invalid hash key")
+	key,err := asHashKey(index)
+	if err != nil{
+		return err
 	}
 
-	pair,ok := hashObj.Pairs[key.HashKey()]
+	value,ok := hashObj.Get(key)
 	if !ok{
 		return NULL
 	}
 
-	return pair.Value
+	return value
 }
 func evalArrayIndexExpression(array,index Object)Object{
 	arrayObj := array.(*Array)
 	idx := index.(*Integer).Value
 
-	max := int64(len(arrayObj.Element)) - 1
+	max := int64(arrayObj.Len()) - 1
+	if idx < 0{
+		idx += max + 1
+	}
+
+	if idx < 0 || idx >max{
+		return newError("index out of range")
+	}
+
+	return arrayObj.At(int(idx))
+}
+
+//按字符下标取得一个字符
+func evalStringIndexExpression(str,index Object)Object{
+	runes := []rune(str.(*StringObject).Value)
+	idx := index.(*Integer).Value
+	if idx < 0{
+		idx += int64(len(runes))
+	}
+
+	if idx < 0 || idx > int64(len(runes)) - 1{
+		return newError("index out of range")
+	}
+
+	return &StringObject{Value:string(runes[idx])}
+}
+
+func evalRangeIndexExpression(rng,index Object)Object{
+	rangeObj := rng.(*Range)
+	idx := index.(*Integer).Value
+	if idx < 0{
+		idx += rangeObj.Len()
+	}
+
+	if idx < 0 || idx >= rangeObj.Len(){
+		return newError("index out of range")
+	}
+
+	return &Integer{Value:rangeObj.At(idx)}
+}
+
+//a..b和a..=b
+func evalRangeExpression(node *ast.RangeExpression,env *Environment)Object{
+	start := Eval(node.Start,env)
+	if isError(start){
+		return start
+	}
+	end := Eval(node.End,env)
+	if isError(end){
+		return end
+	}
+
+	startInt,ok := start.(*Integer)
+	endInt,ok2 := end.(*Integer)
+	if !ok || !ok2{
+		return newError("range bounds must be INTEGER,got %s%s%s",start.Type(),node.TokenLiteral(),end.Type())
+	}
+
+	last := endInt.Value
+	if node.Inclusive{
+		if last == math.MaxInt64{
+			return newError("range %d..=%d overflows",startInt.Value,last)
+		}
+		last++
+	}
+
+	return newRange(startInt.Value,last,1)
+}
+
+//x[a:b]，省略的下标是开头和末尾，负数从末尾算起
+func evalSliceExpression(node *ast.SliceExpression,env *Environment)Object{
+	left := Eval(node.Left,env)
+	if isError(left){
+		return left
+	}
+
+	var size int64
+	switch left := left.(type) {
+	case *Array:
+		size = int64(left.Len())
+	case *Tuple:
+		size = int64(len(left.Element))
+	case *StringObject:
+		size = int64(utf8.RuneCountInString(left.Value))
+	case *Range:
+		size = left.Len()
+	default:
+		return newError("slice operator not supported on %s",left.Type())
+	}
+
+	low,err := evalSliceBound(node.Low,0,size,env)
+	if err != nil{
+		return err
+	}
+	high,err := evalSliceBound(node.High,size,size,env)
+	if err != nil{
+		return err
+	}
+
+	if low < 0 || high > size || low > high{
+		return newError("slice bounds [%d:%d] out of range",low,high)
+	}
+
+	switch left := left.(type) {
+	case *Array:
+		return sliceArray(left,int(low),int(high))
+	case *Range:
+		return &Range{Start:left.bound(low),End:left.bound(high),Step:left.Step}
+	case *Tuple:
+		elements := make([]Object,high - low)
+		copy(elements,left.Element[low:high])
+		return &Tuple{Element:elements}
+	default:
+		runes := []rune(left.(*StringObject).Value)
+		return &StringObject{Value:string(runes[low:high])}
+	}
+}
+
+func evalSliceBound(exp ast.Expression,def int64,size int64,env *Environment)(int64,Object){
+	if exp == nil{
+		return def,nil
+	}
+
+	obj := Eval(exp,env)
+	if isError(obj){
+		return 0,obj
+	}
+
+	bound,ok := obj.(*Integer)
+	if !ok{
+		return 0,newError("slice index must be INTEGER,got %s",obj.Type())
+	}
+	if bound.Value < 0{
+		return bound.Value + size,nil
+	}
+
+	return bound.Value,nil
+}
+
+func evalTupleIndexExpression(tuple,index Object)Object{
+	tupleObj := tuple.(*Tuple)
+	idx := index.(*Integer).Value
+
+	max := int64(len(tupleObj.Element)) - 1
+	if idx < 0{
+		idx += max + 1
+	}
 
 	if idx < 0 || idx >max{
 		return newError("index out of range")
 	}
 
-	return arrayObj.Element[idx]
+	return tupleObj.Element[idx]
 }
 
 
@@ -233,6 +654,10 @@ func evalStringInfixExpression(operator string,
 	switch operator {
 	case "+":
 		return &StringObject{Value:leftVal+rightVal}
+	case "==":
+		return nativeBoolToBooleanObj(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObj(leftVal != rightVal)
 	default:
 		return NULL
 	}
@@ -336,18 +761,31 @@ func evalIdentifier(
 		return val
 }
 
-func applyFunction(fn Object,args []Object)Object{
+//env为调用方所在的环境，builtin通过它回调用户函数
+func applyFunction(fn Object,args []Object,env *Environment)Object{
 	function, ok := fn.(*Function)
 	if ok{
+		if len(args) != len(function.Parameter){
+			return newError("wrong number of arguments.got =%d," +
+				"want=%d", len(args), len(function.Parameter))
+		}
+
 		extendedEnv := extendFunctionEnv(function,args)
-		evaluated := Eval(function.Body,extendedEnv)
-		return unwarapReturnValue(evaluated)
+		return callFunction(function,extendedEnv,env)
 	}
 
 	//看一下是不是builtin function
 	function1,ok := fn.(*Builtin)
 	if ok{
-		return function1.Fn(args...)
+		return function1.Fn(env,args...)
+	}
+
+	if def,ok := fn.(*StructType);ok{
+		return newStruct(def,args)
+	}
+
+	if variant,ok := fn.(*Variant);ok{
+		return newEnumValue(variant,args)
 	}
 
 	return newError("not a function:%s",fn.Type())
@@ -0,0 +1,1055 @@
+package evaluator
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"lexer"
+	"parser"
+)
+
+func testEval(t *testing.T,input string)Object{
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0{
+		t.Fatalf("parser has errors:%v",p.Errors())
+	}
+
+	return Eval(program,NewEnvironment())
+}
+
+func testInspect(t *testing.T,input string,expected string){
+	evaluated := testEval(t,input)
+	if evaluated == nil{
+		t.Errorf("%s: got nil object",input)
+		return
+	}
+
+	if evaluated.Inspect() != expected{
+		t.Errorf("%s: expected %q,got=%q",input,expected,evaluated.Inspect())
+	}
+}
+
+func TestStringBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`split("a,b,c",",")`,"[a,b,c]"},
+		{`len(split("a,b,c",","))`,"3"},
+		{`join(["a","b","c"],"-")`,"a-b-c"},
+		{`join([],"-")`,""},
+		{`trim("  hi  ")`,"hi"},
+		{`trim("xxhixx","x")`,"hi"},
+		{`upper("abc")`,"ABC"},
+		{`lower("ABC")`,"abc"},
+		{`contains("hello","ell")`,"true"},
+		{`contains("hello","xyz")`,"false"},
+		{`index_of("hello","l")`,"2"},
+		{`index_of("hello","z")`,"-1"},
+		{`replace("a.b.c",".","/")`,"a/b/c"},
+		{`starts_with("hello","he")`,"true"},
+		{`ends_with("hello","he")`,"false"},
+		{`repeat("ab",3)`,"ababab"},
+		{`substr("hello",1)`,"ello"},
+		{`substr("hello",1,3)`,"ell"},
+		{`substr("hello",3,10)`,"lo"},
+
+		{`upper(1)`,"ERROR:argument 0 to `upper` must be STRING,got INTEGER"},
+		{`split("a")`,"ERROR:wrong number of arguments.got =1,want=2"},
+		{`join(["a",1],",")`,"ERROR:join: element 1 must be STRING,got INTEGER"},
+		{`repeat("a",-1)`,"ERROR:repeat: negative count -1"},
+		{`substr("abc",4)`,"ERROR:substr: start 4 out of range"},
+		{`substr("abc",1,9223372036854775807)`,"bc"},
+		{`repeat("ab",9223372036854775807)`,"ERROR:repeat: result longer than 1073741824 bytes"},
+		{`repeat("",9223372036854775807)`,""},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestArrayBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`push([1,2],3)`,"[1,2,3]"},
+		{`push([1],2,3)`,"[1,2,3]"},
+		{`let a = [1]; push(a,2); a`,"[1,2]"},
+		{`let a = [1,2,3]; let f = fn(){ a }; push(a,4); f()`,"[1,2,3,4]"},
+		{`first([1,2,3])`,"1"},
+		{`first([])`,"null"},
+		{`last([1,2,3])`,"3"},
+		{`rest([1,2,3])`,"[2,3]"},
+		{`rest([])`,"null"},
+		{`slice([1,2,3,4],1,3)`,"[2,3]"},
+		{`slice([1,2,3,4],2)`,"[3,4]"},
+		{`concat([1],[2,3],[])`,"[1,2,3]"},
+		{`reverse([1,2,3])`,"[3,2,1]"},
+		{`sort([3,1,2])`,"[1,2,3]"},
+		{`sort(["b","c","a"])`,"[a,b,c]"},
+		{`sort([3,1,2],fn(a,b){a > b})`,"[3,2,1]"},
+		{`let a = [3,1,2]; sort(a); a`,"[3,1,2]"},
+		{`let a = [1,2,3]; let r = rest(a); push(r,4); a`,"[1,2,3]"},
+
+		{`first(1)`,"ERROR:argument 0 to `first` must be ARRAY,got INTEGER"},
+		{`slice([1,2],1,5)`,"ERROR:slice: bounds [1:5] out of range"},
+		{`sort([1,"a"])`,"ERROR:sort: cannot compare STRING with INTEGER"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestFunctionalBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`map([1,2,3],fn(x){x * 2})`,"[2,4,6]"},
+		{`map("ab",fn(c){c + c})`,"[aa,bb]"},
+		{`map({"a":1},fn(k,v){v + 1})`,"{a:2}"},
+		{`filter([1,2,3,4],fn(x){x > 2})`,"[3,4]"},
+		{`filter("a-b-c",fn(c){c != "-"})`,"abc"},
+		{`filter({"a":1,"b":2},fn(k,v){v > 1})`,"{b:2}"},
+		{`reduce([1,2,3],fn(acc,x){acc + x},0)`,"6"},
+		{`reduce({"a":1,"b":2},fn(acc,k,v){acc + v},0)`,"3"},
+		{`each([1,2],fn(x){x})`,"null"},
+		{`let a = []; each([1,2],fn(x){push(a,x)}); a`,"[1,2]"},
+		{`any([1,2,3],fn(x){x > 2})`,"true"},
+		{`any([],fn(x){true})`,"false"},
+		{`all([1,2,3],fn(x){x > 0})`,"true"},
+		{`all([1,2,3],fn(x){x > 1})`,"false"},
+		{`map([1],len)`,"ERROR:the type not support len func"},
+
+		{`map(1,fn(x){x})`,"ERROR:argument 0 to `map` must be ARRAY,HASH,SET or STRING,got INTEGER"},
+		{`map([1],fn(x,y){x})`,"ERROR:wrong number of arguments.got =1,want=2"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestHashBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`keys({"a":1})`,"[a]"},
+		{`values({"a":1})`,"[1]"},
+		{`entries({"a":1})`,"[[a,1]]"},
+		{`len(keys({"a":1,"b":2,"c":3}))`,"3"},
+		{`has({"a":1},"a")`,"true"},
+		{`has({"a":1},"b")`,"false"},
+		{`let h = {"a":1,"b":2}; len(keys(delete(h,"a")))`,"1"},
+		{`let h = {"a":1,"b":2}; delete(h,"a"); has(h,"a")`,"true"},
+		{`merge({"a":1},{"a":2})["a"]`,"2"},
+		{`len(keys(merge({"a":1},{"b":2})))`,"2"},
+
+		{`keys([1])`,"ERROR:argument 0 to `keys` must be HASH,got ARRAY"},
+		{`has({},[1])`,"ERROR:invalid hash key:ARRAY must be frozen and contain only hashable values"},
+		{`has({},fn(x){x})`,"ERROR:invalid hash key"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`{"c":1,"a":2,"b":3}`,"{c:1,a:2,b:3}"},
+		{`{3:"x",1:"y",2:"z"}`,"{3:x,1:y,2:z}"},
+		{`keys({"c":1,"a":2,"b":3})`,"[c,a,b]"},
+		{`values({"c":1,"a":2,"b":3})`,"[1,2,3]"},
+		{`{"a":1,"b":2,"a":3}`,"{a:3,b:2}"},
+		{`delete({"c":1,"a":2,"b":3},"a")`,"{c:1,b:3}"},
+		{`merge({"b":1,"a":2},{"c":3,"b":4})`,"{b:4,a:2,c:3}"},
+		{`let log = []; {"x":push(log,1),"y":push(log,2),"z":push(log,3)}; log`,"[1,2,3]"},
+	}
+
+	for _,tt := range tests{
+		for i := 0; i < 5; i++{
+			testInspect(t,tt.input,tt.expected)
+		}
+	}
+}
+
+func TestHashKeyCollision(t *testing.T){
+	//所有key都落到同一个桶里
+	colliding := func(pairs ...Object)*Hash{
+		m := &pmap{root:&hamtNode{},order:emptyVector,hash:func(key Hashable) uint64 {
+			return 42
+		}}
+		for i := 0; i < len(pairs); i += 2{
+			m = m.Set(pairs[i].(Hashable),pairs[i+1])
+		}
+		return &Hash{pairs:m}
+	}
+	str := func(s string)Object{
+		return &StringObject{Value:s}
+	}
+	num := func(n int64)Object{
+		return &Integer{Value:n}
+	}
+
+	ab := colliding(str("a"),num(1),str("b"),num(2))
+	env := NewEnvironment()
+	tests := []struct{
+		result Object
+		expected string
+	}{
+		{ab,"{a:1,b:2}"},
+		{evalIndexExpression(ab,str("a")),"1"},
+		{evalIndexExpression(ab,str("b")),"2"},
+		{evalIndexExpression(ab,str("c")),"null"},
+		{builtins["has"].Fn(env,colliding(str("a"),num(1)),str("b")),"false"},
+		{builtins["delete"].Fn(env,ab,str("a")),"{b:2}"},
+		{builtins["merge"].Fn(env,colliding(str("a"),num(1)),colliding(str("b"),num(2),str("a"),num(3))),"{a:3,b:2}"},
+	}
+
+	for _,tt := range tests{
+		if tt.result.Inspect() != tt.expected{
+			t.Errorf("expected %s,got=%s",tt.expected,tt.result.Inspect())
+		}
+	}
+}
+
+func TestCompositeHashKeys(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`(1,2)`,"(1,2)"},
+		{`(1,)`,"(1,)"},
+		{`()`,"()"},
+		{`(1)`,"1"},
+		{`(1,"a")[1]`,"a"},
+		{`len((1,2,3))`,"3"},
+		{`{(1,2):"x"}[(1,2)]`,"x"},
+		{`{(1,2):"x"}[(2,1)]`,"null"},
+		{`{("alice","2024-01-01"):3}[("alice","2024-01-01")]`,"3"},
+		{`{(1,(2,3)):"nested"}[(1,(2,3))]`,"nested"},
+		{`{freeze([1,2]):"x"}[freeze([1,2])]`,"x"},
+		{`let k = freeze({"a":1,"b":2}); {k:"x"}[freeze({"b":2,"a":1})]`,"x"},
+		{`{(1,2):"x",(1,2):"y"}`,"{(1,2):y}"},
+		{`has({freeze([1]):1},freeze([1]))`,"true"},
+		{`is_frozen(freeze([1]))`,"true"},
+		{`is_frozen([1])`,"false"},
+		{`is_frozen((1,2))`,"true"},
+
+		{`{[1,2]:"x"}`,"ERROR:invalid hash key:ARRAY must be frozen and contain only hashable values"},
+		{`{freeze([[1]]):"x"}`,"ERROR:invalid hash key:ARRAY must be frozen and contain only hashable values"},
+		{`{(1,[2]):"x"}`,"ERROR:invalid hash key:TUPLE must contain only hashable values"},
+		{`push(freeze([1]),2)`,"ERROR:cannot push to frozen array"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestErrorBuiltin(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`error("bad")`,"ERROR:bad"},
+		{`let f = fn(){ error("bad"); 1 }; f()`,"ERROR:bad"},
+		{`error(1)`,"ERROR:argument 0 to `error` must be STRING,got INTEGER"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestSets(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`{1,2,3}`,"{1,2,3}"},
+		{`{1,2,2,1}`,"{1,2}"},
+		{`{"a"}`,"{a}"},
+		{`set()`,"set()"},
+		{`set([3,1,3])`,"{3,1}"},
+		{`set("abca")`,"{a,b,c}"},
+		{`len({1,2,3})`,"3"},
+		{`has({1,2},2)`,"true"},
+		{`has({1,2},3)`,"false"},
+		{`has({(1,2)},(1,2))`,"true"},
+		{`union({1,2},{2,3},{4})`,"{1,2,3,4}"},
+		{`intersection({1,2,3},{3,2,5})`,"{2,3}"},
+		{`intersection({1},{2})`,"set()"},
+		{`difference({1,2,3},{2})`,"{1,3}"},
+		{`subset({1,2},{1,2,3})`,"true"},
+		{`subset({1,4},{1,2,3})`,"false"},
+		{`map({1,2},fn(x){x * 10})`,"[10,20]"},
+		{`filter({1,2,3},fn(x){x > 1})`,"{2,3}"},
+		{`reduce({1,2,3},fn(acc,x){acc + x},0)`,"6"},
+		{`{freeze({1,2}):"x"}[freeze({2,1})]`,"x"},
+
+		{`{[1]}`,"ERROR:invalid hash key:ARRAY must be frozen and contain only hashable values"},
+		{`union({1},[1])`,"ERROR:argument 1 to `union` must be SET,got ARRAY"},
+		{`has([1],1)`,"ERROR:argument 0 to `has` must be HASH or SET,got ARRAY"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestPersistentCollections(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let a = [1,2]; let b = concat(a); push(b,3); a`,"[1,2]"},
+		{`let a = [1,2]; let f = fn(){a}; let b = concat(a,[3]); f()`,"[1,2]"},
+		{`let h = {"a":1}; let g = merge(h,{"b":2}); h`,"{a:1}"},
+		{`let h = {"a":1,"b":2}; let g = delete(h,"a"); [h,g]`,"[{a:1,b:2},{b:2}]"},
+		{`let h = {"a":1,"b":2,"c":3}; delete(merge(h,{"a":4}),"b")`,"{a:4,c:3}"},
+		{`let build = fn(a,n){if (n == 0){a}else{build(push(a,n),n - 1)}}; len(build([],200))`,"200"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestStructs(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`struct Point { x, y }; Point{x: 1, y: 2}`,"Point{x:1,y:2}"},
+		{`struct Point { x, y }; Point{y: 2, x: 1}`,"Point{x:1,y:2}"},
+		{`struct Point { x, y }; Point(3, 4)`,"Point{x:3,y:4}"},
+		{`struct Point { x, y }; Point`,"struct Point{x,y}"},
+		{`struct Point { x, y }; let p = Point{x: 1, y: 2}; p.x + p.y`,"3"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.x = 10; p`,"Point{x:10,y:2}"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.x = p.y = 5; p`,"Point{x:5,y:5}"},
+		{`struct Line { a, b }; struct Point { x, y }; let l = Line(Point(0,0),Point(1,2)); l.b.y`,"2"},
+		{`struct Point { x, y }; {freeze(Point(1,2)):"origin"}[freeze(Point(1,2))]`,"origin"},
+
+		{`struct Point { x, y }; Point{x: 1}`,"ERROR:missing field y in Point literal"},
+		{`struct Point { x, y }; Point{x: 1, y: 2, z: 3}`,"ERROR:struct Point has no field z"},
+		{`struct Point { x, y }; Point{x: 1, x: 2}`,"ERROR:duplicate field x in Point literal"},
+		{`struct Point { x, y }; Point(1)`,"ERROR:wrong number of arguments.got =1,want=2"},
+		{`struct Point { x, y }; Point(1, 2).z`,"ERROR:struct Point has no field z"},
+		{`struct Point { x, y }; let p = freeze(Point(1, 2)); p.x = 3`,"ERROR:cannot assign to field x of frozen Point"},
+		{`struct q { x }; let q = 1; q{x: 1}`,"ERROR:q is not a struct"},
+		{`[1].x`,"ERROR:field access not supported on ARRAY"},
+		{`{"x":1}.x`,"1"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestMethodCalls(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`"abc".upper()`,"ABC"},
+		{`"a,b".split(",")`,"[a,b]"},
+		{`"  hi ".trim().upper()`,"HI"},
+		{`"hello".len()`,"5"},
+		{`let a = [1]; a.push(2); a`,"[1,2]"},
+		{`[3,1,2].sort().reverse()`,"[3,2,1]"},
+		{`["a","b"].join("-")`,"a-b"},
+		{`[1,2,3].map(fn(x){x * 2}).filter(fn(x){x > 2})`,"[4,6]"},
+		{`{"b":1,"a":2}.keys()`,"[b,a]"},
+		{`{"a":1}.has("a")`,"true"},
+		{`{1,2}.union({3})`,"{1,2,3}"},
+		{`(1,2).len()`,"2"},
+		{`let p = {"name":"bob","greet":fn(greeting){greeting + " " + self.name}}; p.greet("hi")`,"hi bob"},
+		{`let h = {"keys":fn(){"custom"}}; h.keys()`,"custom"},
+		{`let h = {"f":len}; h.f("abc")`,"3"},
+		{`struct Counter { n, inc }; let c = Counter(1, fn(){self.n = self.n + 1}); c.inc(); c.n`,"2"},
+
+		{`"abc".push(1)`,"ERROR:unknown method push for STRING"},
+		{`1.upper()`,"ERROR:unknown method upper for INTEGER"},
+		{`{"x":1}.x()`,"ERROR:unknown method x for HASH"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+//方法表中的名字都要有对应的builtin
+func TestMethodTables(t *testing.T){
+	for typ,names := range methods{
+		for name := range names{
+			if _,ok := builtins[name];!ok{
+				t.Errorf("method %s of %s is not a builtin",name,typ)
+			}
+		}
+	}
+}
+
+func TestEnumsAndMatch(t *testing.T){
+	shapes := `enum Shape { Circle(r), Rect(w, h), Empty };
+	let area = fn(s){
+		match (s) {
+			Circle(r) => 3 * r * r,
+			Rect(w, h) if w == h => { w * w }
+			Rect(w, h) => w * h,
+			Empty => 0
+		}
+	};
+	`
+
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`enum Shape { Circle(r), Empty }; Shape`,"enum Shape{Circle(r),Empty}"},
+		{shapes + `Shape.Circle(2)`,"Circle(2)"},
+		{shapes + `Shape.Empty`,"Empty"},
+		{shapes + `Shape.Circle`,"Shape.Circle"},
+		{shapes + `let c = Shape.Circle; c(1)`,"Circle(1)"},
+		{shapes + `Shape.Rect(2, 3).h`,"3"},
+		{shapes + `area(Shape.Circle(2))`,"12"},
+		{shapes + `area(Shape.Rect(2, 2))`,"4"},
+		{shapes + `area(Shape.Rect(2, 3))`,"6"},
+		{shapes + `area(Shape.Empty)`,"0"},
+		{shapes + `[Shape.Circle(1), Shape.Empty].map(area)`,"[3,0]"},
+		{shapes + `let h = {Shape.Circle(1): "one"}; h[Shape.Circle(1)]`,"one"},
+
+		{`match (2) { 1 => "one", 2 => "two", _ => "many" }`,"two"},
+		{`match ("b") { "a" => 1, x => x + "!" }`,"b!"},
+		{`match ((1, 2)) { (a, b) if a > b => a, (a, b) => b }`,"2"},
+		{`match (-1) { -1 => "neg", _ => "pos" }`,"neg"},
+		{`enum Opt { Some(v), None }; match (Opt.Some(Opt.Some(3))) { Some(Some(v)) => v, _ => 0 }`,"3"},
+		{`enum Opt { Some(v), None }; match (Opt.Some(Opt.None)) { Some(Some(v)) => v, Some(None) => -1, _ => 0 }`,"-1"},
+		{`let x = 1; match (5) { x => x }; x`,"1"},
+
+		{shapes + `Shape.Circle(1, 2)`,"ERROR:wrong number of arguments.got =2,want=1"},
+		{shapes + `Shape.Square`,"ERROR:enum Shape has no variant Square"},
+		{shapes + `Shape.Circle(1).w`,"ERROR:variant Circle has no field w"},
+		{`match (3) { 1 => 1, 2 => 2 }`,"ERROR:no match arm for 3"},
+		{shapes + `match (Shape.Circle(1)) { Circle(a, b) => a }`,"ERROR:pattern Circle(a,b) has 2 fields,variant Circle has 1"},
+		{shapes + `{Shape.Circle([1]): 1}`,"ERROR:invalid hash key:ENUM must contain only hashable values"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestLetDestructuring(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let [a, b] = [1, 2]; a + b`,"3"},
+		{`let [a, ...rest] = [1, 2, 3]; rest`,"[2,3]"},
+		{`let [a, b, ...rest] = [1, 2]; rest`,"[]"},
+		{`let [_, second, ..._] = [1, 2, 3, 4]; second`,"2"},
+		{`let [[a, b], c] = [[1, 2], 3]; a + b + c`,"6"},
+		{`let {name, age: years} = {"name": "bob", "age": 3}; years`,"3"},
+		{`let {name} = {"name": "bob", "age": 3}; name`,"bob"},
+		{`let {"first name": first} = {"first name": "al"}; first`,"al"},
+		{`let {pos: [x, y]} = {"pos": [3, 4]}; x * y`,"12"},
+		{`struct Person { name, age }; let {name, age} = Person("al", 5); age`,"5"},
+		{`let (a, b) = (1, 2); b`,"2"},
+		{`let [x, {y}] = [1, {"y": 2}]; x + y`,"3"},
+		{`let f = fn(){ [1, 2] }; let [a, b] = f(); b`,"2"},
+		{`let [X, Y] = [1, 2]; X + Y`,"3"},
+		{`let {pos: (X, y)} = {"pos": (3, 4)}; X * y`,"12"},
+		{`enum Opt { Some(v), None }; let [Some(x), Opt.None] = [Opt.Some(1), Opt.None]; x`,"1"},
+		{`enum Opt { Some(v), None }; let [None] = [Opt.Some(1)]; None`,"Some(1)"},
+		{`match ([1, 2, 3]) { [] => 0, [x] => x, [x, ...rest] => rest }`,"[2,3]"},
+		{`match ({"kind": "circle", "r": 2}) { {kind: "square", side} => side, {kind: "circle", r} => r }`,"2"},
+
+		{`let [a, b] = [1];`,"ERROR:pattern [a,b] does not match [1]:expected 2 elements,got 1"},
+		{`let [a, b, ...c] = [1];`,"ERROR:pattern [a,b,...c] does not match [1]:expected at least 2 elements,got 1"},
+		{`let [a] = 1;`,"ERROR:pattern [a] does not match 1:expected ARRAY,got INTEGER"},
+		{`let {age} = {"name": "bob"};`,"ERROR:pattern {age} does not match {name:bob}:missing key age"},
+		{`let {age} = [1];`,"ERROR:pattern {age} does not match [1]:expected HASH or STRUCT,got ARRAY"},
+		{`let [[a]] = [[1, 2]];`,"ERROR:pattern [[a]] does not match [[1,2]]:expected 1 elements,got 2"},
+		{`let [1, a] = [2, 3];`,"ERROR:pattern [1,a] does not match [2,3]:expected 1,got 2"},
+		{`let [a] = b;`,"ERROR:identifier not found:b"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestConst(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`const x = 1; x`,"1"},
+		{`const [a, b] = [1, 2]; a + b`,"3"},
+		{`const x = 1; let f = fn(){ let x = 2; x }; f() + x`,"3"},
+		{`const a = [1, [2]]; is_frozen(a)`,"true"},
+		{`const a = [1, [2]]; is_frozen(a[1])`,"true"},
+		{`const h = {"k": [1], "s": {"n": 1}}; is_frozen(h["s"])`,"true"},
+		{`const h = {"k": [1]}; is_frozen(h["k"])`,"true"},
+		{`let a = [1]; const b = a; push(a, 2); a`,"[1,2]"},
+		{`let a = [1]; const b = a; push(a, 2); b`,"[1]"},
+		{`const a = [[1]]; {a: 1}[a]`,"1"},
+		{`const [x, inner] = [1, [2]]; is_frozen(inner)`,"true"},
+		{`struct P { x, l }; let p = P(1, [1]); p.l = p; const c = p; c.l.x`,"1"},
+
+		{`const a = [1]; push(a, 2)`,"ERROR:cannot push to frozen array"},
+		{`const a = [[1]]; push(a[0], 2)`,"ERROR:cannot push to frozen array"},
+		{`struct P { x }; const p = P(1); p.x = 2`,"ERROR:cannot assign to field x of frozen P"},
+		{`struct P { x }; const h = {"p": P(1)}; h["p"].x = 2`,"ERROR:cannot assign to field x of frozen P"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+
+	//parser只检查同一段源码，像repl一样分开解析时由求值器检查
+	rebinds := []struct{
+		lines []string
+		expected string
+	}{
+		{[]string{`const x = 1;`,`let x = 2;`},"ERROR:cannot rebind const x"},
+		{[]string{`const x = 1;`,`const x = 2;`},"ERROR:cannot rebind const x"},
+		{[]string{`const c = 3;`,`let [c, d] = [1, 2];`},"ERROR:cannot rebind const c"},
+		{[]string{`const c = 3;`,`let f = fn(){ let c = 4; c }; f()`},"4"},
+	}
+
+	for _,tt := range rebinds{
+		env := NewEnvironment()
+
+		var evaluated Object
+		for _,line := range tt.lines{
+			p := parser.New(lexer.New(line))
+			program := p.ParseProgram()
+			if len(p.Errors()) != 0{
+				t.Fatalf("parser has errors:%v",p.Errors())
+			}
+			evaluated = Eval(program,env)
+		}
+
+		if evaluated == nil || evaluated.Inspect() != tt.expected{
+			t.Errorf("%v: expected %q,got=%v",tt.lines,tt.expected,evaluated)
+		}
+	}
+}
+
+func TestPrint(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`print("hello")`,"hello"},
+		{`print("a", 1, [1,2], true)`,"a 1 [1,2] true"},
+		{`print()`,""},
+		{`println("a"); println(); puts("b", "c")`,"a\n\nb c\n"},
+		{`let f = fn(x){ println(x); x }; f(1) + f(2)`,"1\n2\n"},
+		{`map([1,2],fn(x){ print(x) })`,"12"},
+	}
+
+	for _,tt := range tests{
+		var out bytes.Buffer
+		env := NewEnvironment()
+		env.SetOutput(&out)
+
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0{
+			t.Fatalf("parser has errors:%v",p.Errors())
+		}
+		Eval(program,env)
+
+		if out.String() != tt.expected{
+			t.Errorf("%s: expected output %q,got=%q",tt.input,tt.expected,out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	env := NewEnvironment()
+	env.SetOutput(&out)
+	if result := Eval(parser.New(lexer.New(`print("x")`)).ParseProgram(),env);result != NULL{
+		t.Errorf("print should return null,got %v",result)
+	}
+}
+
+func TestFormat(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`format("%s has %d items", "cart", 3)`,"cart has 3 items"},
+		{`format("%05d|%-4s|%q|%t", 42, "ab", "q", true)`,`00042|ab  |"q"|true`},
+		{`format("%v and %s", [1,2], {"a":1})`,"[1,2] and {a:1}"},
+		{`format("100%%")`,"100%"},
+		{`format("%d", "s")`,"%!d(string=s)"},
+		{`format("%s|%v|%s", 1, 2, true)`,"1|2|true"},
+		{`format("%5s|%-3v|", 12, 3)`,"   12|3  |"},
+		{`format("%% %s", 1)`,"% 1"},
+		{`format("%*d|%s", 3, 7, 8)`,"  7|8"},
+		{`format("%t", 1)`,"%!t(int64=1)"},
+		{`format("%d", true)`,"%!d(bool=true)"},
+		{`format("%d", [1])`,"%!d(string=[1])"},
+		{`format("%d %d", 1)`,"1 %!d(MISSING)"},
+		{`format("%d", 1, 2)`,"1%!(EXTRA int64=2)"},
+		{`format("%s", 1, 2)`,"1%!(EXTRA int64=2)"},
+		{`sprintf("%x", 255)`,"ff"},
+		{`"%s!".format("hi")`,"hi!"},
+		{`format(1)`,"ERROR:argument 0 to `format` must be STRING,got INTEGER"},
+		{`format()`,"ERROR:wrong number of arguments.got =0,want at least 1"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestInterpolatedString(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let name = "bob"; "Hello ${name}!"`,"Hello bob!"},
+		{`let n = 3; "${n} items, ${n * 2} halves"`,"3 items, 6 halves"},
+		{`"${[1, "a"]} ${true} ${(1,2)}"`,"[1,a] true (1,2)"},
+		{`let n = 1; "${"nested ${n + 1}"}"`,"nested 2"},
+		{`let f = fn(x){ "<${x}>" }; f(f("a"))`,"<<a>>"},
+		{`let n = 1; "${n}\t\"\${n}\""`,"1\t\"${n}\""},
+		{`let n = 1; "${"a\nb"} ${n}"`,"a\nb 1"},
+		{`"${missing}"`,"ERROR:identifier not found:missing"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestUnicodeStrings(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`len("héllo")`,"5"},
+		{`len("日本語")`,"3"},
+		{`let 名前 = "naïve"; 名前[2]`,"ï"},
+		{`"日本語"[2]`,"語"},
+		{`"日本語"[3]`,"ERROR:index out of range"},
+		{`"abc"[-4]`,"ERROR:index out of range"},
+		{`substr("héllo",1,3)`,"éll"},
+		{`substr("日本語",3)`,""},
+		{`index_of("日本語","語")`,"2"},
+		{`index_of("日本語","x")`,"-1"},
+		{`slice("héllo",1,3)`,"él"},
+		{`"héllo".slice(2)`,"llo"},
+		{`slice("日本",1,3)`,"ERROR:slice: bounds [1:3] out of range"},
+		{`slice(1,0)`,"ERROR:argument 0 to `slice` must be ARRAY or STRING,got INTEGER"},
+		{`filter("héllo",fn(c){ c != "é" })`,"hllo"},
+		{`map("日本",fn(c){ c + c })`,"[日日,本本]"},
+		{`reduce("héllo",fn(n,c){ n + 1 },0)`,"5"},
+		{`bytes("é")`,"[195,169]"},
+		{`len(bytes("héllo"))`,"6"},
+		{`"ab".bytes()`,"[97,98]"},
+		{`bytes(1)`,"ERROR:argument 0 to `bytes` must be STRING,got INTEGER"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestIndexAndSlice(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`[1,2,3][-1]`,"3"},
+		{`[1,2,3][-3]`,"1"},
+		{`[1,2,3][-4]`,"ERROR:index out of range"},
+		{`(1,2,3)[-2]`,"2"},
+		{`"héllo"[-4]`,"é"},
+		{`let a = [1,2,3,4,5]; a[1:3]`,"[2,3]"},
+		{`let a = [1,2,3,4,5]; a[:2]`,"[1,2]"},
+		{`let a = [1,2,3,4,5]; a[3:]`,"[4,5]"},
+		{`let a = [1,2,3,4,5]; a[:]`,"[1,2,3,4,5]"},
+		{`let a = [1,2,3,4,5]; a[-2:]`,"[4,5]"},
+		{`let a = [1,2,3,4,5]; a[1:-1]`,"[2,3,4]"},
+		{`let a = [1,2,3]; let b = a[:]; push(b,4); a`,"[1,2,3]"},
+		{`[1,2][2:]`,"[]"},
+		{`"héllo"[1:3]`,"él"},
+		{`"héllo"[:-1]`,"héll"},
+		{`"日本語"[2:]`,"語"},
+		{`(1,2,3)[1:]`,"(2,3)"},
+		{`let n = 1; [1,2,3][n:n + 1]`,"[2]"},
+		{`[1,2,3][2:1]`,"ERROR:slice bounds [2:1] out of range"},
+		{`[1,2,3][:5]`,"ERROR:slice bounds [0:5] out of range"},
+		{`[1,2,3][-5:]`,"ERROR:slice bounds [-2:3] out of range"},
+		{`[1,2,3]["a":]`,"ERROR:slice index must be INTEGER,got STRING"},
+		{`{"a":1}[0:1]`,"ERROR:slice operator not supported on HASH"},
+		{`[1,2][missing]`,"ERROR:identifier not found:missing"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestRanges(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`0..5`,"0..5"},
+		{`1..=3`,"1..4"},
+		{`let n = 3; 0..n - 1`,"0..2"},
+		{`len(0..5)`,"5"},
+		{`len(5..0)`,"0"},
+		{`len(range(0, 1e9))`,"1000000000"},
+		{`range(0, 1e9)[-1]`,"999999999"},
+		{`(0..10)[3]`,"3"},
+		{`(0..10)[10]`,"ERROR:index out of range"},
+		{`(0..10)[2:5]`,"2..5"},
+		{`range(10)`,"0..10"},
+		{`range(0, 10, 3).to_array()`,"[0,3,6,9]"},
+		{`range(10, 0, -3).to_array()`,"[10,7,4,1]"},
+		{`len(range(10, 0, -3))`,"4"},
+		{`range(0, 10, 3)[1:]`,"range(3,12,3)"},
+		{`range(0, 10, 3)[1:].to_array()`,"[3,6,9]"},
+		{`map(1..=3, fn(x){ x * x })`,"[1,4,9]"},
+		{`filter(0..10, fn(x){ x > 7 })`,"[8,9]"},
+		{`reduce(range(1e6), fn(a, x){ a + x }, 0)`,"499999500000"},
+		{`any(range(0, 1e18), fn(x){ x > 10 })`,"true"},
+		{`(0..3).len()`,"3"},
+		{`range(1, 2, 0)`,"ERROR:range: step cannot be 0"},
+		{`range("a")`,"ERROR:argument 0 to `range` must be INTEGER,got STRING"},
+		{`range()`,"ERROR:wrong number of arguments.got =0,want=1 to 3"},
+		{`1.."a"`,"ERROR:range bounds must be INTEGER,got INTEGER..STRING"},
+		{`1e3 + 1`,"1001"},
+		{`len(range(0, 9223372036854775807, 2))`,"4611686018427387904"},
+		{`len(range(0, 9223372036854775807))`,"9223372036854775807"},
+		{`len(range(9223372036854775807, -9223372036854775807, -9223372036854775807))`,"2"},
+		{`range(0, 9223372036854775807, 2)[-1]`,"9223372036854775806"},
+		{`range(0, 9223372036854775807, 2)[1:]`,"range(2,9223372036854775807,2)"},
+		{`len(range(0, 9223372036854775807, 2)[1:])`,"4611686018427387903"},
+		{`len(range(-9000000000000000000, 9000000000000000000, 2))`,"9000000000000000000"},
+		{`range(-9000000000000000000, 9000000000000000000)`,
+			"ERROR:range -9000000000000000000..9000000000000000000 has too many elements"},
+		{`-9000000000000000000..9000000000000000000`,
+			"ERROR:range -9000000000000000000..9000000000000000000 has too many elements"},
+		{`len(0..=9223372036854775806)`,"9223372036854775807"},
+		{`0..=9223372036854775807`,"ERROR:range 0..=9223372036854775807 overflows"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestIterators(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let it = iter([1,2]); [next(it),next(it),next(it),next(it)]`,"[1,2,null,null]"},
+		{`let it = iter(0..3); next(it); to_array(it)`,"[1,2]"},
+		{`let it = (0..3).iter(); it.next(); it.to_array()`,"[1,2]"},
+		{`let a = [1,2]; let it = iter(a); push(a,3); to_array(it)`,"[1,2]"},
+		{`iter("héllo").to_array()`,"[h,é,l,l,o]"},
+		{`iter((1,2)).to_array()`,"[1,2]"},
+		{`iter({"a":1,"b":2}).to_array()`,"[(a,1),(b,2)]"},
+		{`to_array(0..3)`,"[0,1,2]"},
+		{`let it = iter([1,2,3]); map(it,fn(x){ x * 2 })`,"[2,4,6]"},
+		{`let it = iter([1,2,3]); reduce(it,fn(a,x){ a + x },0); to_array(it)`,"[]"},
+		{`let it = iter(0..10); any(it,fn(x){ x == 3 }); next(it)`,"4"},
+		{`iter([1])`,"iterator"},
+		{`iter(1)`,"ERROR:argument 0 to `iter` must be iterable,got INTEGER"},
+		{`next([1])`,"ERROR:argument 0 to `next` must be ITERATOR,got ARRAY"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+func TestGenerators(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let g = fn*(){ yield 1; yield 2 }; let it = g(); [next(it),next(it),next(it)]`,"[1,2,null]"},
+		{`let count = fn*(n){ let loop = fn(i){ if(i < n){ yield i; loop(i + 1) } }; loop(0) }; to_array(count(4))`,"[0,1,2,3]"},
+		{`let squares = fn*(r){ each(r,fn(x){ yield x * x }) }; squares(1..4).to_array()`,"[1,4,9]"},
+		{`let nat = fn*(){ let loop = fn(i){ yield i; loop(i + 1) }; loop(0) }; let it = nat(); next(it); next(it); next(it)`,"2"},
+		{`let g = fn*(){ let x = yield; x }; to_array(g())`,"[null]"},
+		{`let g = fn*(){ yield 1 }; g()`,"iterator"},
+		{`fn*(x){ yield x }`,"fn*(x){\nyield x\n"},
+		{`let g = fn*(){ yield 1; yield 2 }; let it = g(); next(it); close(it); next(it)`,"null"},
+		{`let g = fn*(){ yield 1; [1][5] }; let it = g(); next(it); next(it)`,"ERROR:index out of range"},
+		{`let g = fn*(){ yield fn(){ yield 1 } }; next(g())()`,"ERROR:yield outside running generator"},
+		//函数体中使用自己的迭代器
+		{`let gen = fn*(){ yield 1; yield next(it) }; let it = gen(); next(it); next(it)`,"ERROR:generator already running"},
+		{`let gen = fn*(){ yield 1; close(it); yield 2; yield 3 }; let it = gen(); to_array(it)`,"[1]"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+
+	//提前关闭生成器时执行defer
+	outputs := []struct{
+		input string
+		expected string
+	}{
+		{`let g = fn*(){ defer println("done"); yield 1; yield 2 }; let it = g(); next(it); close(it); close(it)`,"done\n"},
+		{`let g = fn*(){ defer println("done"); yield 1 }; to_array(g())`,"done\n"},
+		{`let g = fn*(){ defer println("done"); yield 1 }; g()`,""},
+		{`let g = fn*(){ defer print("done"); yield 1; close(it); yield 2 }; let it = g(); to_array(it)`,"done"},
+		//提前结束遍历时也关闭生成器
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; any(g(),fn(x){ true })`,"done"},
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; all(g(),fn(x){ false })`,"done"},
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; map(g(),fn(x){ [1][5] })`,"done"},
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; g().each(fn(x){ [1][5] })`,"done"},
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; reduce(g(),fn(a,x){ a + "a" },0)`,"done"},
+		{`let g = fn*(){ defer print("done"); yield 1; yield 2 }; set(g()); print("!")`,"done!"},
+	}
+
+	for _,tt := range outputs{
+		var out bytes.Buffer
+		env := NewEnvironment()
+		env.SetOutput(&out)
+
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0{
+			t.Fatalf("parser has errors:%v",p.Errors())
+		}
+		Eval(program,env)
+
+		if out.String() != tt.expected{
+			t.Errorf("%s: expected output %q,got=%q",tt.input,tt.expected,out.String())
+		}
+	}
+}
+//没有执行完就被丢掉的生成器，迭代器被回收后goroutine也结束
+func TestGeneratorsAreReclaimed(t *testing.T){
+	before := runtime.NumGoroutine()
+
+	testInspect(t,`let g = fn*(){ defer print("never"); yield 1; yield 2 };
+		len(map(range(2000),fn(i){ let it = g(); next(it) }))`,"2000")
+
+	for i := 0; i < 100 && runtime.NumGoroutine() > before + 10; i++{
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine();n > before + 10{
+		t.Fatalf("expected abandoned generators to be reclaimed,got %d goroutines,had %d",n,before)
+	}
+}
+
+func TestDefer(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let f = fn(){ defer print("a"); defer print("b"); print("c") }; f()`,"cba"},
+		{`let f = fn(x){ defer print(x); let x = 2; print(x) }; f(1)`,"22"},
+		{`let f = fn(){ defer print("d"); return 1; print("x") }; f()`,"d"},
+		{`let f = fn(){ defer print("d"); [1][5] }; f()`,"d"},
+		{`let f = fn(){ defer print("d"); 1 }; let g = fn(){ f(); print("g") }; g()`,"dg"},
+	}
+
+	for _,tt := range tests{
+		var out bytes.Buffer
+		env := NewEnvironment()
+		env.SetOutput(&out)
+
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0{
+			t.Fatalf("parser has errors:%v",p.Errors())
+		}
+		Eval(program,env)
+
+		if out.String() != tt.expected{
+			t.Errorf("%s: expected output %q,got=%q",tt.input,tt.expected,out.String())
+		}
+	}
+
+	testInspect(t,`let f = fn(){ defer [1][5]; 1 }; f()`,"ERROR:index out of range")
+	testInspect(t,`let f = fn(){ defer 1 + 2; 3 }; f()`,"3")
+}
+func TestChannels(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let c = chan(2); send(c, 1); c.send(2); [recv(c), c.recv()]`,"[1,2]"},
+		{`let c = chan(3); send(c, 1); send(c, 2); close(c); [recv(c), recv(c), recv(c)]`,"[1,2,null]"},
+		{`let c = chan(3); send(c, 1); send(c, 2); close(c); to_array(c)`,"[1,2]"},
+		{`let c = chan(3); send(c, 1); send(c, 2); close(c); map(c, fn(x){ x * 10 })`,"[10,20]"},
+		{`let c = chan(1); let a = [1]; send(c, a); push(a, 2); recv(c)`,"[1]"},
+		{`let c = chan(1); send(c, [1]); is_frozen(recv(c))`,"true"},
+		{`chan(4)`,"channel(4)"},
+		{`let c = chan(); close(c); send(c, 1)`,"ERROR:send on closed channel"},
+		{`let c = chan(); close(c); close(c)`,"ERROR:close of closed channel"},
+		{`chan(-1)`,"ERROR:chan: size cannot be negative,got -1"},
+		{`chan(9223372036854775807)`,"ERROR:chan: size cannot be larger than 1048576,got 9223372036854775807"},
+		{`chan("a")`,"ERROR:argument 0 to `chan` must be INTEGER,got STRING"},
+		{`recv([1])`,"ERROR:argument 0 to `recv` must be CHANNEL,got ARRAY"},
+		{`close(1)`,"ERROR:argument 0 to `close` must be ITERATOR or CHANNEL,got INTEGER"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+func TestSpawn(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`recv(spawn fn(){ 1 + 2 })`,"3"},
+		{`let sq = fn(x){ x * x }; let tasks = map(1..6, fn(i){ spawn sq(i) }); map(tasks, recv)`,"[1,4,9,16,25]"},
+		{`let c = chan(); let r = spawn fn(){ send(c, 1); send(c, 2); close(c) }; to_array(c)`,"[1,2]"},
+		{`recv(spawn len("abc"))`,"3"},
+		{`let r = spawn fn(){ 1 }; recv(r); recv(r)`,"null"},
+		{`recv(spawn fn(){ [1][5] })`,"ERROR:index out of range"},
+		{`is_frozen(recv(spawn fn(){ [1] }))`,"true"},
+		{`spawn 1`,"ERROR:cannot spawn INTEGER"},
+		{`spawn f(1)`,"ERROR:identifier not found:f"},
+		//任务中看到的外层可变值是冻结的拷贝
+		{`let xs = [1]; recv(spawn fn(){ push(xs, 2) })`,"ERROR:cannot push to frozen array"},
+		{`let xs = [1]; recv(spawn fn(){ len(xs) }); push(xs, 2); xs`,"[1,2]"},
+		{`let f = fn(xs){ push(xs, 2) }; let xs = [1]; recv(spawn f(xs))`,"ERROR:cannot push to frozen array"},
+		//通过辅助函数和参数中的函数间接修改也不行
+		{`let arr = [1]; let add = fn(x){ push(arr, x) }; let t = spawn fn(){ add(2) }; add(5); recv(t)`,
+			"ERROR:cannot push to frozen array"},
+		{`let arr = [1]; let add = fn(x){ push(arr, x) }; let t = spawn fn(){ len(arr) }; add(5); [recv(t), arr]`,"[1,[1,5]]"},
+		{`let arr = [1]; let fs = [fn(){ push(arr, 2) }]; recv(spawn fn(){ fs[0]() })`,"ERROR:cannot push to frozen array"},
+		{`let arr = [1]; let c = chan(1); send(c, fn(){ push(arr, 2) }); recv(c)()`,"ERROR:cannot push to frozen array"},
+		{`let fib = fn(n){ if(n < 2){ n }else{ fib(n - 1) + fib(n - 2) } }; recv(spawn fib(10))`,"55"},
+		//迭代器不能交给其他任务
+		{`let g = fn*(){ yield 1; yield 2; yield 3 }; let it = g(); let a = spawn fn(){ next(it) }; next(it); recv(a)`,
+			"ERROR:cannot share ITERATOR with another task"},
+		{`let it = iter([1]); spawn next(it)`,"ERROR:cannot share ITERATOR with another task"},
+		{`let it = iter([1]); let h = fn(){ next(it) }; spawn fn(){ h() }`,"ERROR:cannot share ITERATOR with another task"},
+		{`let c = chan(1); send(c, [iter([1])])`,"ERROR:cannot share ITERATOR with another task"},
+		{`let c = chan(1); select { send(c, iter([1])) => 1 }`,"ERROR:cannot share ITERATOR with another task"},
+		{`recv(spawn fn(){ iter([1]) })`,"ERROR:cannot share ITERATOR with another task"},
+		{`recv(spawn fn(){ to_array(iter([1, 2])) })`,"[1,2]"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+
+	//任务同时读外层环境和输出，外层环境同时增加新的名字
+	var out bytes.Buffer
+	env := NewEnvironment()
+	env.SetOutput(&out)
+
+	input := `let n = 1; let tasks = map(0..20, fn(i){ spawn fn(){ print(n) } }); let m = 2; each(tasks, recv)`
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0{
+		t.Fatalf("parser has errors:%v",p.Errors())
+	}
+	Eval(program,env)
+
+	if out.String() != strings.Repeat("1",20){
+		t.Errorf("expected 20 outputs,got %q",out.String())
+	}
+}
+//任务通过捕获了外层数组的辅助函数修改它，外层同时也在修改；
+//用go test -race运行时检查没有数据竞争
+func TestSpawnSharedClosures(t *testing.T){
+	input := `let arr = [1];
+		let add = fn(x){ push(arr, x) };
+		let tasks = map(0..8, fn(i){ spawn fn(){ add(i); len(arr) } });
+		each(0..100, fn(i){ add(i) });
+		[tasks, arr]`
+
+	evaluated := testEval(t,input)
+	result,ok := evaluated.(*Array)
+	if !ok{
+		t.Fatalf("expected an array,got %s",evaluated.Inspect())
+	}
+
+	for _,task := range result.At(0).(*Array).Elements(){
+		value,_ := task.(*Channel).Recv(false)
+		if value.Inspect() != "ERROR:cannot push to frozen array"{
+			t.Errorf("expected the task to fail,got %s",value.Inspect())
+		}
+	}
+	if n := result.At(1).(*Array).Len();n != 101{
+		t.Errorf("expected 101 elements,got %d",n)
+	}
+}
+
+//主程序等待的通道不会再有任务让它就绪时返回错误
+func TestChannelDeadlock(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`send(chan(), 1)`,"ERROR:deadlock: send would block forever"},
+		{`recv(chan())`,"ERROR:deadlock: recv would block forever"},
+		{`let c = chan(1); c.send(1); c.send(2)`,"ERROR:deadlock: send would block forever"},
+		{`let c = chan(); select { v = recv(c) => v }`,"ERROR:deadlock: select would block forever"},
+		{`to_array(chan())`,"ERROR:deadlock: recv would block forever"},
+		{`map(chan(), fn(x){ x })`,"ERROR:deadlock: recv would block forever"},
+		{`let c = chan(); spawn fn(){ 1 }; recv(c)`,"ERROR:deadlock: recv would block forever"},
+		{`let c = chan(); let d = chan(); spawn fn(){ recv(d) }; recv(c)`,"ERROR:deadlock: recv would block forever"},
+
+		//任务之间还能通信时不是死锁
+		{`let c = chan(); spawn fn(){ each(0..100, fn(i){ send(c, i) }); close(c) }; reduce(c, fn(a, x){ a + x }, 0)`,"4950"},
+		{`let c = chan(); let r = spawn fn(){ spawn fn(){ send(c, 1) }; recv(c) + 1 }; recv(r)`,"2"},
+		{`let a = chan(); let b = chan(); spawn fn(){ each(0..200, fn(i){ send(b, recv(a) + 1) }) };
+			reduce(0..200, fn(n, i){ send(a, n); recv(b) }, 0)`,"200"},
+		{`let a = chan(); spawn fn(){ each(0..100, fn(i){ send(a, i) }) };
+			recv(spawn fn(){ reduce(0..100, fn(s, i){ s + recv(a) }, 0) })`,"4950"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
+
+func TestSelect(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let c = chan(1); send(c, 5); select { v = recv(c) => v + 1, _ => 0 }`,"6"},
+		{`let c = chan(); select { v = recv(c) => v, _ => "none" }`,"none"},
+		{`let c = chan(1); select { send(c, 9) => "sent" }; recv(c)`,"9"},
+		{`let c = chan(); close(c); select { v = recv(c) => v }`,"null"},
+		{`let c = chan(1); send(c, 1); close(c); select { v = recv(c) => v }`,"1"},
+		{`let c = chan(); close(c); select { send(c, 1) => 1 }`,"ERROR:send on closed channel"},
+		{`let a = chan(); let b = chan(1); send(b, "b"); select { v = recv(a) => v, v = recv(b) => v }`,"b"},
+		{`let c = chan(); let r = spawn fn(){ send(c, 7) }; select { v = recv(c) => v * 2 }`,"14"},
+		{`let a = chan(); let b = chan(); spawn fn(){ send(a, 1) }; spawn fn(){ send(b, 2) };
+			let pick = fn(){ select { v = recv(a) => v, v = recv(b) => v } }; pick() + pick()`,"3"},
+		{`let c = chan(); spawn fn(){ select { send(c, 1) => 0, v = recv(c) => v } }; recv(c)`,"1"},
+		{`select { v = recv(1) => v }`,"ERROR:select case must be CHANNEL,got INTEGER"},
+		{`let c = chan(1); select { v = recv(c) => v, _ => { let x = 1; x + 1 } }`,"2"},
+	}
+
+	for _,tt := range tests{
+		testInspect(t,tt.input,tt.expected)
+	}
+}
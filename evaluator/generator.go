@@ -0,0 +1,238 @@
+package evaluator
+
+import (
+	"ast"
+	"runtime"
+)
+
+//一次函数调用的状态，保存在函数的环境中
+type callFrame struct {
+	defers []deferred
+	gen *generator //生成器函数的调用才有
+	task bool //在spawn的任务中，从调用方继承
+}
+
+type deferred struct {
+	call ast.Expression
+	env *Environment
+}
+
+//按注册的相反顺序执行defer，返回第一个错误
+func (f *callFrame)runDefers()Object{
+	var first Object
+	for i := len(f.defers) - 1; i >= 0; i--{
+		d := f.defers[i]
+		if result := Eval(d.call,d.env);isError(result) && first == nil{
+			first = result
+		}
+	}
+	f.defers = nil
+
+	return first
+}
+
+//离当前环境最近的函数调用
+func (e *Environment)callFrame()*callFrame{
+	for env := e; env != nil; env = env.outer{
+		if env.frame != nil{
+			return env.frame
+		}
+	}
+
+	return nil
+}
+
+//当前代码是不是在spawn的任务中执行。调用处的环境最近的callFrame就是正在执行的调用，
+//所以这里得到的是动态的调用链上的信息
+func (e *Environment)inTask()bool{
+	frame := e.callFrame()
+	return frame != nil && frame.task
+}
+
+//词法上最近的生成器调用，生成器中的回调通过它yield
+func (e *Environment)generator()*generator{
+	for env := e; env != nil; env = env.outer{
+		if env.frame != nil && env.frame.gen != nil{
+			return env.frame.gen
+		}
+	}
+
+	return nil
+}
+
+//执行函数体，env是绑定好参数的环境，caller是调用处的环境；
+//生成器函数不执行，返回一个迭代器
+func callFunction(function *Function,env *Environment,caller *Environment)Object{
+	frame := &callFrame{task:caller.inTask()}
+	env.frame = frame
+
+	if function.Generator{
+		return newGenerator(function.Body,env)
+	}
+
+	result := unwarapReturnValue(Eval(function.Body,env))
+	if err := frame.runDefers();err != nil && !isError(result){
+		return err
+	}
+
+	return result
+}
+
+//生成器被关闭时yield返回它，像错误一样结束函数体的执行
+var errGeneratorClosed = &Error{Message:"generator closed"}
+
+//生成器的函数体在单独的goroutine中执行，和调用方轮流运行：
+//next让它运行到下一个yield，yield把值交给调用方后等待下一次next。
+//没有执行完也没有关闭的生成器，在迭代器被回收时结束goroutine，这时defer不会执行；
+//函数体中引用了自己的迭代器时迭代器不会被回收，goroutine一直留到程序结束
+type generator struct {
+	body *ast.BlockStatement
+	env *Environment
+
+	yields chan Object //函数体执行完后关闭
+	resume chan bool //false表示关闭生成器
+	started bool
+	running bool //函数体正在执行，只由调用方修改
+	finished bool
+	stopping bool
+	abandoned bool //迭代器已经被回收
+	err Object //函数体或defer中的错误
+}
+
+func newGenerator(body *ast.BlockStatement,env *Environment)*Iterator{
+	g := &generator{
+		body:body,
+		env:env,
+		yields:make(chan Object),
+		resume:make(chan bool),
+	}
+	env.frame.gen = g
+
+	it := NewIterator(g.next)
+	it.stop = g.stop
+	runtime.SetFinalizer(it, func(*Iterator) {
+		g.abandon()
+	})
+	return it
+}
+
+func (g *generator)run(){
+	defer close(g.yields)
+
+	result := unwarapReturnValue(Eval(g.body,g.env))
+	err := g.env.frame.runDefers()
+
+	switch {
+	case isError(result) && result != errGeneratorClosed:
+		g.err = result
+	case err != nil && err != errGeneratorClosed:
+		g.err = err
+	}
+}
+
+func (g *generator)next()(Object,bool){
+	if g.finished{
+		return nil,false
+	}
+	//函数体中对自己的迭代器调用next，函数体就是调用方，不能等自己yield
+	if g.running{
+		return newError("generator already running"),true
+	}
+
+	g.running = true
+	if !g.started{
+		g.started = true
+		go g.run()
+	}else{
+		g.resume <- true
+	}
+
+	value,ok := <-g.yields
+	g.running = false
+	if ok{
+		return value,true
+	}
+
+	//执行完了，错误作为最后一个值
+	g.finished = true
+	if err := g.err;err != nil{
+		g.err = nil
+		return err,true
+	}
+	return nil,false
+}
+
+//提前结束：让暂停中的yield返回errGeneratorClosed，等函数体执行完defer；
+//函数体中关闭自己时，函数体在下一个yield处结束
+func (g *generator)stop()Object{
+	if !g.started || g.finished{
+		g.finished = true
+		return nil
+	}
+	if g.running{
+		g.stopping = true
+		return nil
+	}
+
+	g.stopping = true
+	g.resume <- false
+	for range g.yields{
+	}
+	g.finished = true
+
+	return g.err
+}
+
+func (g *generator)yield(value Object)Object{
+	if g.stopping{
+		return errGeneratorClosed
+	}
+
+	g.yields <- value
+	if !<-g.resume{
+		if g.abandoned{
+			//不再执行任何代码，直接结束goroutine
+			runtime.Goexit()
+		}
+		return errGeneratorClosed
+	}
+
+	return NULL
+}
+
+//迭代器被回收时调用，这时调用方不会再调用next，函数体一定暂停在yield中
+func (g *generator)abandon(){
+	if g.started && !g.finished{
+		g.abandoned = true
+		g.resume <- false
+	}
+}
+
+//生成器暂停时，逃逸出去的回调不能yield
+func evalYieldExpression(node *ast.YieldExpression,env *Environment)Object{
+	gen := env.generator()
+	if gen == nil || !gen.running{
+		return newError("yield outside running generator")
+	}
+
+	var value Object = NULL
+	if node.Value != nil{
+		value = Eval(node.Value,env)
+		if isError(value){
+			return value
+		}
+	}
+
+	return gen.yield(value)
+}
+
+//记下表达式和环境，函数返回时再求值
+func evalDeferStatement(node *ast.DeferStatement,env *Environment)Object{
+	frame := env.callFrame()
+	if frame == nil{
+		return newError("defer outside function")
+	}
+
+	frame.defers = append(frame.defers,deferred{call:node.Call,env:env})
+	return nil
+}
@@ -0,0 +1,217 @@
+package evaluator
+
+import (
+	"ast"
+	"fmt"
+)
+
+func newEnumType(node *ast.EnumStatement)*EnumType{
+	enum := &EnumType{Name:node.Name.Value}
+
+	for _,v := range node.Variants{
+		fields := []string{}
+		for _,f := range v.Fields{
+			fields = append(fields,f.Value)
+		}
+		enum.Variants = append(enum.Variants,&Variant{Enum:enum,Name:v.Name.Value,Fields:fields})
+	}
+
+	return enum
+}
+
+func newEnumValue(variant *Variant,args []Object)Object{
+	if len(args) != len(variant.Fields){
+		return newError("wrong number of arguments.got =%d," +
+			"want=%d", len(args), len(variant.Fields))
+	}
+
+	values := make([]Object,len(args))
+	copy(values,args)
+
+	return &EnumValue{Variant:variant,Values:values}
+}
+
+//按顺序尝试每个分支，模式中绑定的名字只在该分支内可见
+func evalMatchExpression(node *ast.MatchExpression,env *Environment)Object{
+	subject := Eval(node.Subject,env)
+	if isError(subject){
+		return subject
+	}
+
+	for _,arm := range node.Arms{
+		armEnv := NewEnclosedEnvironment(env)
+
+		matched,err := matchPattern(arm.Pattern,subject,armEnv)
+		if err != nil{
+			return err
+		}
+		if !matched{
+			continue
+		}
+
+		if arm.Guard != nil{
+			guard := Eval(arm.Guard,armEnv)
+			if isError(guard){
+				return guard
+			}
+			if !isTurthy(guard){
+				continue
+			}
+		}
+
+		result := Eval(arm.Body,armEnv)
+		if result == nil{
+			return NULL
+		}
+		return result
+	}
+
+	return newError("no match arm for %s",subject.Inspect())
+}
+
+//值符合模式时把绑定写入env，模式本身写错时返回错误
+func matchPattern(pattern ast.Pattern,value Object,env *Environment)(bool,Object){
+	mismatch,err := destructure(pattern,value,env)
+	return mismatch == "",err
+}
+
+//let解构，值的结构与模式不符时报错
+func evalLetPattern(pattern ast.Pattern,value Object,env *Environment)Object{
+	mismatch,err := destructure(pattern,value,env)
+	if err != nil{
+		return err
+	}
+	if mismatch != ""{
+		return newError("pattern %s does not match %s:%s",
+			pattern.String(),value.Inspect(),mismatch)
+	}
+
+	return nil
+}
+
+//返回值与模式不符的原因，匹配时返回空字符串
+func destructure(pattern ast.Pattern,value Object,env *Environment)(string,Object){
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return "",nil
+
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value,value)
+		return "",nil
+
+	case *ast.LiteralPattern:
+		literal := Eval(pattern.Value,env)
+		if isError(literal){
+			return "",literal
+		}
+		if !keysEqual(literal,value){
+			return fmt.Sprintf("expected %s,got %s",literal.Inspect(),value.Inspect()),nil
+		}
+		return "",nil
+
+	case *ast.TuplePattern:
+		tuple,ok := value.(*Tuple)
+		if !ok{
+			return expectedType(TUPLE_OBJ,value),nil
+		}
+		if len(tuple.Element) != len(pattern.Elements){
+			return fmt.Sprintf("expected %d elements,got %d",len(pattern.Elements),len(tuple.Element)),nil
+		}
+		return destructureAll(pattern.Elements,tuple.Element,env)
+
+	case *ast.ArrayPattern:
+		return destructureArray(pattern,value,env)
+
+	case *ast.HashPattern:
+		return destructureHash(pattern,value,env)
+
+	case *ast.VariantPattern:
+		enumValue,ok := value.(*EnumValue)
+		if !ok{
+			return expectedType(ENUM_OBJ,value),nil
+		}
+		if enumValue.Variant.Name != pattern.Name.Value ||
+			(pattern.Enum != nil && enumValue.Variant.Enum.Name != pattern.Enum.Value){
+			return fmt.Sprintf("expected %s,got %s",pattern.Name.Value,enumValue.Variant.Inspect()),nil
+		}
+		if len(pattern.Fields) != len(enumValue.Values){
+			return "",newError("pattern %s has %d fields,variant %s has %d",
+				pattern.String(),len(pattern.Fields),enumValue.Variant.Name,len(enumValue.Values))
+		}
+		return destructureAll(pattern.Fields,enumValue.Values,env)
+	}
+
+	return "",newError("unknown pattern %s",pattern.String())
+}
+
+func destructureAll(patterns []ast.Pattern,values []Object,env *Environment)(string,Object){
+	for i,p := range patterns{
+		mismatch,err := destructure(p,values[i],env)
+		if err != nil || mismatch != ""{
+			return mismatch,err
+		}
+	}
+
+	return "",nil
+}
+
+//rest绑定剩下的元素组成的新数组
+func destructureArray(pattern *ast.ArrayPattern,value Object,env *Environment)(string,Object){
+	array,ok := value.(*Array)
+	if !ok{
+		return expectedType(ARRAY_OBJ,value),nil
+	}
+
+	elements := array.Elements()
+	if pattern.Rest == nil && len(elements) != len(pattern.Elements){
+		return fmt.Sprintf("expected %d elements,got %d",len(pattern.Elements),len(elements)),nil
+	}
+	if len(elements) < len(pattern.Elements){
+		return fmt.Sprintf("expected at least %d elements,got %d",len(pattern.Elements),len(elements)),nil
+	}
+
+	mismatch,err := destructureAll(pattern.Elements,elements,env)
+	if err != nil || mismatch != ""{
+		return mismatch,err
+	}
+
+	if pattern.Rest != nil{
+		return destructure(pattern.Rest,NewArray(elements[len(pattern.Elements):]),env)
+	}
+
+	return "",nil
+}
+
+//hash按字符串key取值，结构体按字段名取值
+func destructureHash(pattern *ast.HashPattern,value Object,env *Environment)(string,Object){
+	for _,entry := range pattern.Entries{
+		var field Object
+		var ok bool
+
+		switch value := value.(type) {
+		case *Hash:
+			field,ok = value.Get(&StringObject{Value:entry.Key.Value})
+		case *Struct:
+			field,ok = value.Get(entry.Key.Value)
+		case *Module:
+			field,ok = value.Get(entry.Key.Value)
+		default:
+			return expectedType(HASH_OBJ + " or " + STRUCT_OBJ,value),nil
+		}
+
+		if !ok{
+			return fmt.Sprintf("missing key %s",entry.Key.Value),nil
+		}
+
+		mismatch,err := destructure(entry.Value,field,env)
+		if err != nil || mismatch != ""{
+			return mismatch,err
+		}
+	}
+
+	return "",nil
+}
+
+func expectedType(want ObjectType,got Object)string{
+	return fmt.Sprintf("expected %s,got %s",want,got.Type())
+}
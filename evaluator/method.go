@@ -0,0 +1,111 @@
+package evaluator
+
+import "ast"
+
+//每种类型的方法表，方法就是把接收者作为第一个参数的builtin
+//"abc".upper()等价于upper("abc")
+//表中只记名字，调用时再查builtins，不依赖各文件init的顺序
+var methods = map[ObjectType]map[string]bool{}
+
+func registerMethods(t ObjectType,names ...string){
+	if methods[t] == nil{
+		methods[t] = make(map[string]bool)
+	}
+
+	for _,name := range names{
+		methods[t][name] = true
+	}
+}
+
+func init(){
+	registerMethods(STRING_OBJ,"len","split","trim","upper","lower","contains",
+		"index_of","replace","starts_with","ends_with","repeat","substr","format",
+		"slice","bytes","iter","map","filter","reduce","each","any","all")
+	registerMethods(ARRAY_OBJ,"len","push","first","last","rest","slice",
+		"concat","reverse","sort","join","map","filter","reduce","each",
+		"any","all","iter","freeze","is_frozen")
+	registerMethods(TUPLE_OBJ,"len","iter","map","filter","reduce","each","any","all")
+	registerMethods(HASH_OBJ,"len","keys","values","entries","has","delete",
+		"merge","map","filter","reduce","each","any","all","iter","freeze","is_frozen")
+	registerMethods(SET_OBJ,"len","has","union","intersection","difference",
+		"subset","map","filter","reduce","each","any","all","iter","freeze","is_frozen")
+	registerMethods(STRUCT_OBJ,"freeze","is_frozen")
+	registerMethods(RANGE_OBJ,"len","iter","to_array","map","filter","reduce","each",
+		"any","all")
+	registerMethods(ITERATOR_OBJ,"next","close","to_array","map","filter","reduce","each",
+		"any","all")
+	registerMethods(CHANNEL_OBJ,"send","recv","close","iter","to_array","map","filter",
+		"reduce","each","any","all")
+}
+
+func evalMethodCall(node *ast.MethodCallExpression,env *Environment)Object{
+	receiver := Eval(node.Receiver,env)
+	if isError(receiver){
+		return receiver
+	}
+
+	args := evalExpression(node.Arguments,env)
+
+	return callMethod(receiver,node.Method.Value,args,env)
+}
+
+//hash、结构体和模块中保存的函数优先，其次查找类型的方法表
+func callMethod(receiver Object,name string,args []Object,env *Environment)Object{
+	if fn,ok := attachedMethod(receiver,name);ok{
+		return applyMethod(fn,receiver,args,env)
+	}
+
+	if methods[receiver.Type()][name]{
+		if method,ok := builtins[name];ok{
+			return method.Fn(env,append([]Object{receiver},args...)...)
+		}
+	}
+
+	return newError("unknown method %s for %s",name,receiver.Type())
+}
+
+func attachedMethod(receiver Object,name string)(Object,bool){
+	var value Object
+	var ok bool
+
+	switch receiver := receiver.(type) {
+	case *Hash:
+		value,ok = receiver.Get(&StringObject{Value:name})
+	case *Struct:
+		value,ok = receiver.Get(name)
+	case *EnumType:
+		//Shape.Circle(1)调用分支的构造函数
+		value,ok = receiver.Variant(name)
+	case *Module:
+		value,ok = receiver.Get(name)
+	}
+
+	if !ok{
+		return nil,false
+	}
+
+	switch value.(type) {
+	case *Function,*Builtin,*Variant:
+		return value,true
+	default:
+		return nil,false
+	}
+}
+
+//用户函数作为方法调用时，接收者绑定到self
+func applyMethod(fn Object,self Object,args []Object,env *Environment)Object{
+	function,ok := fn.(*Function)
+	if !ok{
+		return applyFunction(fn,args,env)
+	}
+
+	if len(args) != len(function.Parameter){
+		return newError("wrong number of arguments.got =%d," +
+			"want=%d", len(args), len(function.Parameter))
+	}
+
+	extendedEnv := extendFunctionEnv(function,args)
+	extendedEnv.Set("self",self)
+
+	return callFunction(function,extendedEnv,env)
+}
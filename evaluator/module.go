@@ -0,0 +1,152 @@
+package evaluator
+
+import (
+	"ast"
+	"io/fs"
+	"lexer"
+	"parser"
+	"path"
+	"strings"
+	"sync"
+)
+
+//模块源文件的扩展名，import "lib/math"读取lib/math.monkey
+const ModuleExt = ".monkey"
+
+//模块加载器，从fs.FS(可以是os.DirFS或embed.FS)中读取模块
+//每个模块在自己的环境中只求值一次，之后的import直接返回缓存
+type ModuleLoader struct {
+	fsys fs.FS
+	modules map[string]*Module
+	loading []string //正在加载的模块，用于检测循环导入
+	mu sync.Mutex //spawn的任务可能同时import，加载期间一直持有
+	prelude bool //加载的是标准库本身，模块的环境中还没有标准库
+	programs map[string]*ast.Program //预先解析好的模块
+}
+
+func NewModuleLoader(fsys fs.FS)*ModuleLoader{
+	return &ModuleLoader{fsys:fsys,modules:make(map[string]*Module)}
+}
+
+func (l *ModuleLoader)Load(name string)Object{
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.load(name,nil)
+}
+
+//模块的环境继承导入者的输出
+func (l *ModuleLoader)load(name string,importer *Environment)Object{
+	name = path.Clean(name)
+	if !fs.ValidPath(name){
+		return newError("invalid module path %s",name)
+	}
+
+	if module,ok := l.modules[name];ok{
+		return module
+	}
+
+	for i,loading := range l.loading{
+		if loading == name{
+			cycle := append(append([]string{},l.loading[i:]...),name)
+			return newError("import cycle:%s",strings.Join(cycle," -> "))
+		}
+	}
+
+	program,ok := l.programs[name]
+	if !ok{
+		src,err := fs.ReadFile(l.fsys,name + ModuleExt)
+		if err != nil{
+			return newError("cannot import %s:%s",name,err)
+		}
+
+		p := parser.New(lexer.New(string(src)))
+		program = p.ParseProgram()
+		if len(p.Errors()) != 0{
+			return newError("parse error in module %s:%s",name,strings.Join(p.Errors(),";"))
+		}
+	}
+
+	l.loading = append(l.loading,name)
+	defer func(){
+		l.loading = l.loading[:len(l.loading)-1]
+	}()
+
+	//模块和用户的程序一样可以使用标准库，加载标准库本身时还没有
+	var env *Environment
+	if l.prelude{
+		env = NewEnvironment()
+	}else{
+		env = NewRootEnvironment()
+	}
+	env.SetModuleLoader(l)
+	env.importing = true
+	if importer != nil{
+		env.SetOutput(importer.out)
+	}
+
+	result := Eval(program,env)
+	if err,ok := result.(*Error);ok{
+		return newError("error in module %s:%s",name,err.Message)
+	}
+
+	module := &Module{Name:name,Exports:env.exported()}
+	l.modules[name] = module
+
+	return module
+}
+
+//模块导出的名字和值，只读
+func (e *Environment)exported()*Hash{
+	exports := NewHash()
+	for _,name := range e.exports{
+		exports.Set(&StringObject{Value:name},e.store[name])
+	}
+	exports.Frozen = true
+
+	return exports
+}
+
+//import的结果，m.name取得导出的值
+type Module struct {
+	Name string
+	Exports *Hash
+}
+
+func (m *Module)Type()ObjectType{
+	return MODULE_OBJ
+}
+func (m *Module)Inspect()string{
+	return "module " + m.Name
+}
+
+func (m *Module)Get(name string)(Object,bool){
+	return m.Exports.Get(&StringObject{Value:name})
+}
+
+func evalImportExpression(node *ast.ImportExpression,env *Environment)Object{
+	if env.loader == nil{
+		return newError("cannot import %s:no module loader",node.Path.Value)
+	}
+
+	//模块中的import在加载这个模块时已经持有锁
+	if !env.importing{
+		env.loader.mu.Lock()
+		defer env.loader.mu.Unlock()
+	}
+
+	return env.loader.load(node.Path.Value,env)
+}
+
+func evalExportStatement(node *ast.ExportStatement,env *Environment)Object{
+	result := Eval(node.Statement,env)
+	if isError(result){
+		return result
+	}
+
+	for _,name := range node.Names(){
+		env.exports = append(env.exports,name.Value)
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+package evaluator
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"lexer"
+	"parser"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/modules
+var testModules embed.FS
+
+func testEvalModules(t *testing.T,loader *ModuleLoader,input string)Object{
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0{
+		t.Fatalf("parser has errors:%v",p.Errors())
+	}
+
+	env := NewEnvironment()
+	env.SetModuleLoader(loader)
+
+	return Eval(program,env)
+}
+
+func TestModules(t *testing.T){
+	fsys := fstest.MapFS{
+		"lib/math.monkey":{Data:[]byte(`
+			let helper = fn(x){ x * x };
+			export let square = fn(x){ helper(x) };
+			export const origin = [0,0];
+			export struct Point { x, y }
+			export enum Color { Red, Green }
+		`)},
+		"lib/counter.monkey":{Data:[]byte(`
+			let m = import "lib/math";
+			export let count = m.square(3);
+		`)},
+		"cycle/a.monkey":{Data:[]byte(`import "cycle/b"; export let a = 1;`)},
+		"cycle/b.monkey":{Data:[]byte(`import "cycle/a"; export let b = 2;`)},
+		"broken.monkey":{Data:[]byte(`let x = ;`)},
+		"failing.monkey":{Data:[]byte(`-true;`)},
+		"stats.monkey":{Data:[]byte(`
+			export let total = fn(xs){ sum(xs) };
+			export let sum = fn(xs){ "shadowed" };
+		`)},
+		"uses_prelude.monkey":{Data:[]byte(`export let mean = fn(xs){ sum(xs) / len(xs) };`)},
+	}
+
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let m = import "lib/math"; m.square(4)`,"16"},
+		{`import "lib/math".square(5)`,"25"},
+		{`let m = import "lib/math"; m`,"module lib/math"},
+		{`let {square,origin} = import "lib/math"; [square(2),origin]`,"[4,[0,0]]"},
+		{`let m = import "lib/math"; let Point = m.Point; Point(1,2).x`,"1"},
+		{`let m = import "lib/math"; m.Color.Green`,"Green"},
+		{`let m = import "./lib/../lib/math"; m.square(3)`,"9"},
+		{`import "lib/counter".count`,"9"},
+		{`let m = import "lib/math"; m.origin.is_frozen()`,"true"},
+		{`let m = import "lib/math"; m.helper`,"ERROR:module lib/math has no export helper"},
+		{`import "missing"`,"ERROR:cannot import missing:open missing.monkey: file does not exist"},
+		{`import "../outside"`,"ERROR:invalid module path ../outside"},
+		{`import "cycle/a"`,"ERROR:error in module cycle/a:error in module cycle/b:import cycle:cycle/a -> cycle/b -> cycle/a"},
+		{`import "broken"`,"ERROR:parse error in module broken:no prefix parse function for ; found "},
+		{`import "failing"`,"ERROR:error in module failing:unkown operator:BOOLEAN"},
+		{`import "uses_prelude".mean([1,2,3])`,"2"},
+		{`import "stats".total([1,2])`,"shadowed"},
+	}
+
+	for _,tt := range tests{
+		evaluated := testEvalModules(t,NewModuleLoader(fsys),tt.input)
+		if evaluated == nil || evaluated.Inspect() != tt.expected{
+			t.Errorf("%s: expected %q,got=%v",tt.input,tt.expected,evaluated)
+		}
+	}
+}
+
+func TestModules_EvaluatedOnce(t *testing.T){
+	fsys := fstest.MapFS{
+		"state.monkey":{Data:[]byte(`export let items = [];`)},
+	}
+	loader := NewModuleLoader(fsys)
+
+	first := testEvalModules(t,loader,`import "state"`)
+	second := testEvalModules(t,loader,`import "./state"`)
+	if first != second{
+		t.Errorf("expected the same module object,got %v and %v",first,second)
+	}
+}
+
+func TestModules_EmbedFS(t *testing.T){
+	fsys,err := fs.Sub(testModules,"testdata/modules")
+	if err != nil{
+		t.Fatal(err)
+	}
+
+	evaluated := testEvalModules(t,NewModuleLoader(fsys),`import "text/greet".greet("world")`)
+	if evaluated.Inspect() != "hello world"{
+		t.Errorf("expected hello world,got %q",evaluated.Inspect())
+	}
+}
+
+func TestModules_NoLoader(t *testing.T){
+	testInspect(t,`import "lib/math"`,"ERROR:cannot import lib/math:no module loader")
+}
+
+func TestModules_Output(t *testing.T){
+	fsys := fstest.MapFS{
+		"log.monkey":{Data:[]byte(`println("loading"); export let info = fn(msg){ println("info:", msg) };`)},
+	}
+
+	var out bytes.Buffer
+	env := NewEnvironment()
+	env.SetModuleLoader(NewModuleLoader(fsys))
+	env.SetOutput(&out)
+
+	p := parser.New(lexer.New(`let log = import "log"; log.info("ready");`))
+	Eval(p.ParseProgram(),env)
+
+	if out.String() != "loading\ninfo: ready\n"{
+		t.Errorf("expected module output in the importer's writer,got %q",out.String())
+	}
+}
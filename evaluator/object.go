@@ -5,12 +5,29 @@ import (
 	"ast"
 	"bytes"
 	"strings"
+	"hash"
 	"hash/fnv"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sync"
 )
 
 const (
 	HASH_OBJ = "HASH"
 	ARRAY_OBJ = "ARRAY"
+	TUPLE_OBJ = "TUPLE"
+	SET_OBJ = "SET"
+	STRUCT_TYPE_OBJ = "STRUCT_TYPE"
+	STRUCT_OBJ = "STRUCT"
+	ENUM_TYPE_OBJ = "ENUM_TYPE"
+	VARIANT_OBJ = "VARIANT"
+	ENUM_OBJ = "ENUM"
+	MODULE_OBJ = "MODULE"
+	RANGE_OBJ = "RANGE"
+	ITERATOR_OBJ = "ITERATOR"
+	CHANNEL_OBJ = "CHANNEL"
 	BUILTIN_OBJ = "BUILTIN"
 	STRING_OBJ = "STRING"
 	INTEGER_OBJ = "INTEGER"
@@ -23,6 +40,7 @@ const (
 )
 
 type Hashable interface {
+	Object
 	HashKey()HashKey
 }
 
@@ -111,10 +129,18 @@ func (e *Error)Inspect()string{
 	return "ERROR:"+e.Message
 }
 
-//环境
+//环境，spawn的任务和创建它的代码可能同时访问外层环境，读写store时加锁
 type Environment struct {
+	mu sync.RWMutex
 	outer *Environment
 	store map[string]Object
+	consts map[string]bool //用const声明的名字，不能重新绑定
+
+	loader *ModuleLoader //import通过它加载模块，内层环境继承外层的
+	importing bool //正在加载的模块中，import不再对加载器加锁
+	out io.Writer //print输出的位置，nil时是标准输出，内层环境继承外层的
+	frame *callFrame //函数调用的环境才有，记录defer和生成器
+	exports []string //模块顶层用export声明的名字
 }
 func NewEnvironment()*Environment{
 	s := make(map[string]Object)
@@ -122,7 +148,9 @@ func NewEnvironment()*Environment{
 }
 
 func (e *Environment)Get(name string)(Object,bool){
+	e.mu.RLock()
 	obj,ok := e.store[name]
+	e.mu.RUnlock()
 	if !ok && e.outer != nil{
 		obj,ok = e.outer.Get(name)
 	}
@@ -131,13 +159,66 @@ func (e *Environment)Get(name string)(Object,bool){
 }
 
 func (e *Environment)Set(name string,obj Object)Object{
+	e.mu.Lock()
 	e.store[name] = obj
+	e.mu.Unlock()
 	return obj
 }
 
+func (e *Environment)SetConst(name string,obj Object)Object{
+	e.mu.Lock()
+	if e.consts == nil{
+		e.consts = make(map[string]bool)
+	}
+	e.consts[name] = true
+	e.mu.Unlock()
+
+	return e.Set(name,obj)
+}
+
+//只检查当前作用域，内层作用域可以遮蔽外层的const
+func (e *Environment)IsConst(name string)bool{
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.consts[name]
+}
+
+//所有能访问到的名字，包括外层环境的
+func (e *Environment)Names()[]string{
+	names := []string{}
+	for env := e; env != nil; env = env.outer{
+		env.mu.RLock()
+		for name := range env.store{
+			names = append(names,name)
+		}
+		env.mu.RUnlock()
+	}
+
+	return names
+}
+
+//设置模块加载器，没有加载器的环境中import会报错
+func (e *Environment)SetModuleLoader(loader *ModuleLoader){
+	e.loader = loader
+}
+
+func (e *Environment)SetOutput(out io.Writer){
+	e.out = out
+}
+
+func (e *Environment)Output()io.Writer{
+	if e.out == nil{
+		return os.Stdout
+	}
+	return e.out
+}
+
 func NewEnclosedEnvironment(outer *Environment)*Environment{
 	env := NewEnvironment()
 	env.outer = outer
+	env.loader = outer.loader
+	env.importing = outer.importing
+	env.out = outer.out
 	return env
 }
 
@@ -145,6 +226,7 @@ type Function struct {
 	Parameter []*ast.Indetifier
 	Body *ast.BlockStatement
 	Env *Environment
+	Generator bool //调用时返回迭代器
 }
 func (f *Function)Type()ObjectType{
 	return FUNCTION_OBJ
@@ -158,6 +240,9 @@ func (f *Function)Inspect()string{
 	}
 
 	out.WriteString("fn")
+	if f.Generator{
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params,","))
 	out.WriteString("){\n")
@@ -179,14 +264,18 @@ func (s *StringObject)Inspect()string{
 }
 
 func (s *StringObject)HashKey()HashKey{
+	return HashKey{Type:s.Type(),Value:hashString(s.Value)}
+}
+
+func hashString(s string)uint64{
 	h := fnv.New64a()
-	h.Write([]byte(s.Value))
+	h.Write([]byte(s))
 
-	return HashKey{Type:s.Type(),Value:h.Sum64()}
+	return h.Sum64()
 }
 
 //
-type BuiltInFunction func(args ...Object)Object
+type BuiltInFunction func(env *Environment,args ...Object)Object
 type Builtin struct {
 	Fn BuiltInFunction
 }
@@ -199,9 +288,60 @@ func (b *Builtin)Inspect()string{
 }
 
 //array object
+//底层是持久化向量，修改只生成新版本，不会影响共享同一数据的其他数组
+//冻结后不能再修改，元素都可以hash时可以作为hash的key
 type Array struct {
-	Element []Object
+	elements *pvector
+	Frozen bool
+}
+
+func NewArray(elements []Object)*Array{
+	return &Array{elements:newVector(elements)}
+}
+
+func (a *Array)Len()int{
+	return a.elements.Len()
+}
+
+func (a *Array)At(i int)Object{
+	return a.elements.At(i)
+}
+
+//返回元素的副本
+func (a *Array)Elements()[]Object{
+	return a.elements.Slice()
+}
+
+//按顺序遍历，fn返回false时停止
+func (a *Array)Each(fn func(i int,e Object)bool){
+	a.elements.Each(fn)
 }
+
+//在原数组上追加，之前复制出去的数组不受影响
+//只有push这样原地修改，替换的是底层vector的指针，已经取得的快照和副本仍然有效；
+//spawn交给任务的是冻结的拷贝，同一个数组不会在两个goroutine中被修改
+func (a *Array)Push(objs ...Object){
+	for _,o := range objs{
+		a.elements = a.elements.Append(o)
+	}
+}
+
+//返回追加元素后的新数组
+func (a *Array)Append(objs ...Object)*Array{
+	elements := a.elements
+	for _,o := range objs{
+		elements = elements.Append(o)
+	}
+
+	return &Array{elements:elements}
+}
+
+
+//与原数组共享数据，复制是O(1)的
+func (a *Array)Copy()*Array{
+	return &Array{elements:a.elements}
+}
+
 func (a *Array)Type()ObjectType{
 	return ARRAY_OBJ
 }
@@ -209,9 +349,10 @@ func (a *Array)Inspect()string{
 	var out bytes.Buffer
 
 	ele := []string{}
-	for _, e := range a.Element{
+	a.Each(func(i int, e Object) bool {
 		ele = append(ele,e.Inspect())
-	}
+		return true
+	})
 
 	out.WriteString("[")
 	out.WriteString(strings.Join(ele,","))
@@ -219,6 +360,37 @@ func (a *Array)Inspect()string{
 
 	return out.String()
 }
+func (a *Array)HashKey()HashKey{
+	return HashKey{Type:a.Type(),Value:combineHashKeys(a.Elements())}
+}
+
+//元组，创建后不可修改
+type Tuple struct {
+	Element []Object
+}
+func (t *Tuple)Type()ObjectType{
+	return TUPLE_OBJ
+}
+func (t *Tuple)Inspect()string{
+	var out bytes.Buffer
+
+	ele := []string{}
+	for _, e := range t.Element{
+		ele = append(ele,e.Inspect())
+	}
+
+	out.WriteString("(")
+	out.WriteString(strings.Join(ele,","))
+	if len(ele) == 1{
+		out.WriteString(",")
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+func (t *Tuple)HashKey()HashKey{
+	return HashKey{Type:t.Type(),Value:combineHashKeys(t.Element)}
+}
 
 //hash
 type HashKey struct {
@@ -231,8 +403,218 @@ type HashPair struct {
 	Value Object
 }
 
+//hash按插入顺序保存key，保证打印和遍历的结果是确定的
+//底层是持久化的HAMT，HashKey只用来定位，冲突的key再比较实际的值。
+//Copy、With和Without都只生成新版本，不复制全部键值对，delete和merge用它们
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	pairs *pmap
+	Frozen bool
+}
+
+func NewHash()*Hash{
+	return &Hash{pairs:emptyPmap}
+}
+
+func (h *Hash)Get(key Hashable)(Object,bool){
+	return h.pairs.Get(key)
+}
+
+//在原hash上修改，已存在的key保留原来的位置
+func (h *Hash)Set(key Hashable,value Object){
+	h.pairs = h.pairs.Set(key,value)
+}
+
+func (h *Hash)Delete(key Hashable){
+	h.pairs = h.pairs.Delete(key)
+}
+
+//返回设置key后的新hash
+func (h *Hash)With(key Hashable,value Object)*Hash{
+	return &Hash{pairs:h.pairs.Set(key,value)}
+}
+
+//返回删除key后的新hash
+func (h *Hash)Without(key Hashable)*Hash{
+	return &Hash{pairs:h.pairs.Delete(key)}
+}
+
+func (h *Hash)Len()int{
+	return h.pairs.Len()
+}
+
+//按插入顺序遍历，fn返回false时停止
+func (h *Hash)Each(fn func(key Hashable,value Object)bool){
+	h.pairs.Each(fn)
+}
+
+//按插入顺序返回所有键值对
+func (h *Hash)Pairs()[]HashPair{
+	pairs := make([]HashPair,0,h.Len())
+	h.Each(func(key Hashable, value Object) bool {
+		pairs = append(pairs,HashPair{Key:key,Value:value})
+		return true
+	})
+
+	return pairs
+}
+
+//与原hash共享数据，复制是O(1)的
+func (h *Hash)Copy()*Hash{
+	return &Hash{pairs:h.pairs}
+}
+
+//比较两个key的实际值，数组、元组和hash按结构比较
+func keysEqual(a,b Object)bool{
+	if a.Type() != b.Type(){
+		return false
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value == b.(*Integer).Value
+	case *Boolean:
+		return a.Value == b.(*Boolean).Value
+	case *StringObject:
+		return a.Value == b.(*StringObject).Value
+	case *Array:
+		return elementsEqual(a.Elements(),b.(*Array).Elements())
+	case *Tuple:
+		return elementsEqual(a.Element,b.(*Tuple).Element)
+	case *Struct:
+		other := b.(*Struct)
+		return a.Def == other.Def && elementsEqual(a.Values,other.Values)
+	case *EnumValue:
+		other := b.(*EnumValue)
+		return a.Variant == other.Variant && elementsEqual(a.Values,other.Values)
+	case *Set:
+		other := b.(*Set)
+		if a.Len() != other.Len(){
+			return false
+		}
+		for _,e := range a.Elements(){
+			if !other.Has(e.(Hashable)){
+				return false
+			}
+		}
+
+		return true
+	case *Hash:
+		other := b.(*Hash)
+		if a.Len() != other.Len(){
+			return false
+		}
+		equal := true
+		a.Each(func(key Hashable, value Object) bool {
+			otherValue,ok := other.Get(key)
+			equal = ok && keysEqual(value,otherValue)
+			return equal
+		})
+
+		return equal
+	default:
+		return a == b
+	}
+}
+
+func elementsEqual(a,b []Object)bool{
+	if len(a) != len(b){
+		return false
+	}
+
+	for i := range a{
+		if !keysEqual(a[i],b[i]){
+			return false
+		}
+	}
+
+	return true
+}
+
+//检查对象能否作为hash的key
+//数组和hash必须先冻结，并且其中的元素也都能作为key
+func isHashable(obj Object)bool{
+	switch obj := obj.(type) {
+	case *Integer,*Boolean,*StringObject:
+		return true
+	case *Tuple:
+		return allHashable(obj.Element)
+	case *EnumValue:
+		return allHashable(obj.Values)
+	case *Array:
+		return obj.Frozen && allHashable(obj.Elements())
+	case *Set:
+		return obj.Frozen
+	case *Struct:
+		return obj.Frozen && allHashable(obj.Values)
+	case *Hash:
+		if !obj.Frozen{
+			return false
+		}
+		hashable := true
+		obj.Each(func(key Hashable, value Object) bool {
+			hashable = isHashable(value)
+			return hashable
+		})
+
+		return hashable
+	default:
+		return false
+	}
+}
+
+func allHashable(elements []Object)bool{
+	for _,e := range elements{
+		if !isHashable(e){
+			return false
+		}
+	}
+
+	return true
+}
+
+func asHashKey(obj Object)(Hashable,Object){
+	if !isHashable(obj){
+		switch obj.Type() {
+		case ARRAY_OBJ,HASH_OBJ,SET_OBJ,STRUCT_OBJ:
+			return nil,newError("invalid hash key:%s must be frozen " +
+				"and contain only hashable values",obj.Type())
+		case TUPLE_OBJ,ENUM_OBJ:
+			return nil,newError("invalid hash key:%s must contain " +
+				"only hashable values",obj.Type())
+		}
+
+		return nil,newError("invalid hash key")
+	}
+
+	return obj.(Hashable),nil
+}
+
+func combineHashKeys(elements []Object)uint64{
+	h := fnv.New64a()
+	for _,e := range elements{
+		writeHashKey(h,e.(Hashable).HashKey())
+	}
+
+	return h.Sum64()
+}
+
+func writeHashKey(h hash.Hash64,key HashKey){
+	var buf [8]byte
+
+	h.Write([]byte(key.Type))
+	binary.LittleEndian.PutUint64(buf[:],key.Value)
+	h.Write(buf[:])
+}
+
+//hash的key没有顺序，各键值对的hash相加，与插入顺序无关
+func (h *Hash)HashKey()HashKey{
+	var sum uint64
+	h.Each(func(key Hashable, value Object) bool {
+		sum += combineHashKeys([]Object{key,value})
+		return true
+	})
+
+	return HashKey{Type:h.Type(),Value:sum}
 }
 
 func (h *Hash)Type()ObjectType{
@@ -242,7 +624,7 @@ func (h *Hash)Inspect()string{
 	var out bytes.Buffer
 
 	s := []string{}
-	for _,v := range h.Pairs{
+	for _,v := range h.Pairs(){
 		s = append(s,v.Key.Inspect() + ":" + v.Value.Inspect())
 	}
 
@@ -253,3 +635,399 @@ func (h *Hash)Inspect()string{
 	return out.String()
 }
 
+
+//集合，复用Hash的存储，按插入顺序保存元素
+type Set struct {
+	members *Hash
+	Frozen bool
+}
+
+func NewSet()*Set{
+	return &Set{members:NewHash()}
+}
+
+func (s *Set)Add(member Hashable){
+	s.members.Set(member,member)
+}
+
+func (s *Set)Has(member Hashable)bool{
+	_,ok := s.members.Get(member)
+	return ok
+}
+
+func (s *Set)Len()int{
+	return s.members.Len()
+}
+
+func (s *Set)Elements()[]Object{
+	elements := make([]Object,0,s.Len())
+	s.members.Each(func(key Hashable, value Object) bool {
+		elements = append(elements,key)
+		return true
+	})
+
+	return elements
+}
+
+func (s *Set)Type()ObjectType{
+	return SET_OBJ
+}
+func (s *Set)Inspect()string{
+	if s.Len() == 0{
+		return "set()"
+	}
+
+	var out bytes.Buffer
+
+	ele := []string{}
+	for _,e := range s.Elements(){
+		ele = append(ele,e.Inspect())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(ele,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+//与hash一样，集合的hash与元素顺序无关
+func (s *Set)HashKey()HashKey{
+	var sum uint64
+	for _,e := range s.Elements(){
+		sum += combineHashKeys([]Object{e})
+	}
+
+	return HashKey{Type:s.Type(),Value:sum}
+}
+
+//结构体类型，由struct声明创建，调用它可以按位置构造实例
+type StructType struct {
+	Name string
+	Fields []string
+}
+
+func (st *StructType)Type()ObjectType{
+	return STRUCT_TYPE_OBJ
+}
+func (st *StructType)Inspect()string{
+	return "struct " + st.Name + "{" + strings.Join(st.Fields,",") + "}"
+}
+
+func (st *StructType)fieldIndex(name string)int{
+	for i,f := range st.Fields{
+		if f == name{
+			return i
+		}
+	}
+
+	return -1
+}
+
+//结构体实例，字段按声明的顺序保存
+type Struct struct {
+	Def *StructType
+	Values []Object
+	Frozen bool
+}
+
+func (s *Struct)Type()ObjectType{
+	return STRUCT_OBJ
+}
+func (s *Struct)Inspect()string{
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i,f := range s.Def.Fields{
+		fields = append(fields,f + ":" + s.Values[i].Inspect())
+	}
+
+	out.WriteString(s.Def.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields,","))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (s *Struct)Get(field string)(Object,bool){
+	idx := s.Def.fieldIndex(field)
+	if idx < 0{
+		return nil,false
+	}
+
+	return s.Values[idx],true
+}
+
+func (s *Struct)HashKey()HashKey{
+	return HashKey{Type:s.Type(),Value:combineHashKeys(s.Values) ^ hashString(s.Def.Name)}
+}
+
+//枚举类型，由enum声明创建，Shape.Circle取得其中的分支
+type EnumType struct {
+	Name string
+	Variants []*Variant
+}
+
+func (et *EnumType)Type()ObjectType{
+	return ENUM_TYPE_OBJ
+}
+func (et *EnumType)Inspect()string{
+	variants := []string{}
+	for _,v := range et.Variants{
+		variants = append(variants,v.signature())
+	}
+
+	return "enum " + et.Name + "{" + strings.Join(variants,",") + "}"
+}
+
+func (et *EnumType)Variant(name string)(*Variant,bool){
+	for _,v := range et.Variants{
+		if v.Name == name{
+			return v,true
+		}
+	}
+
+	return nil,false
+}
+
+//枚举的分支，调用它构造枚举值
+type Variant struct {
+	Enum *EnumType
+	Name string
+	Fields []string
+}
+
+func (v *Variant)Type()ObjectType{
+	return VARIANT_OBJ
+}
+func (v *Variant)Inspect()string{
+	return v.Enum.Name + "." + v.Name
+}
+
+func (v *Variant)signature()string{
+	if len(v.Fields) == 0{
+		return v.Name
+	}
+
+	return v.Name + "(" + strings.Join(v.Fields,",") + ")"
+}
+
+//枚举值，不可修改，字段都能作为key时它也能作为key
+type EnumValue struct {
+	Variant *Variant
+	Values []Object
+}
+
+func (ev *EnumValue)Type()ObjectType{
+	return ENUM_OBJ
+}
+func (ev *EnumValue)Inspect()string{
+	if len(ev.Values) == 0{
+		return ev.Variant.Name
+	}
+
+	values := []string{}
+	for _,v := range ev.Values{
+		values = append(values,v.Inspect())
+	}
+
+	return ev.Variant.Name + "(" + strings.Join(values,",") + ")"
+}
+
+func (ev *EnumValue)Get(field string)(Object,bool){
+	for i,f := range ev.Variant.Fields{
+		if f == field{
+			return ev.Values[i],true
+		}
+	}
+
+	return nil,false
+}
+
+func (ev *EnumValue)HashKey()HashKey{
+	name := ev.Variant.Enum.Name + "." + ev.Variant.Name
+	return HashKey{Type:ev.Type(),Value:combineHashKeys(ev.Values) ^ hashString(name)}
+}
+
+//惰性的整数区间，不保存元素，不包含End
+type Range struct {
+	Start int64
+	End int64
+	Step int64
+}
+
+func (r *Range)Type()ObjectType{
+	return RANGE_OBJ
+}
+func (r *Range)Inspect()string{
+	if r.Step == 1{
+		return fmt.Sprintf("%d..%d",r.Start,r.End)
+	}
+	return fmt.Sprintf("range(%d,%d,%d)",r.Start,r.End,r.Step)
+}
+
+//元素个数，区间都由newRange创建，个数不会超过int64
+func (r *Range)Len()int64{
+	n,_ := r.length()
+	return int64(n)
+}
+
+//跨度用uint64计算，不会溢出，个数超过int64时ok为false
+func (r *Range)length()(n uint64,ok bool){
+	var span,stride uint64
+	switch {
+	case r.Step > 0 && r.End > r.Start:
+		span,stride = uint64(r.End) - uint64(r.Start),uint64(r.Step)
+	case r.Step < 0 && r.Start > r.End:
+		span,stride = uint64(r.Start) - uint64(r.End),-uint64(r.Step)
+	default:
+		return 0,true
+	}
+
+	n = (span - 1) / stride + 1
+	return n,n <= math.MaxInt64
+}
+
+//第i个元素，不检查范围
+func (r *Range)At(i int64)int64{
+	return r.Start + i * r.Step
+}
+
+//切片的边界，i等于长度时是最后一个元素之后的值，超出int64时用End
+func (r *Range)bound(i int64)int64{
+	size := r.Len()
+	if i < size{
+		return r.At(i)
+	}
+	if size == 0{
+		return r.End
+	}
+
+	last := r.At(size - 1)
+	if (r.Step > 0 && last > math.MaxInt64 - r.Step) || (r.Step < 0 && last < math.MinInt64 - r.Step){
+		return r.End
+	}
+	return last + r.Step
+}
+
+//元素个数超过int64的区间不能使用，创建时报错
+func newRange(start,end,step int64)Object{
+	r := &Range{Start:start,End:end,Step:step}
+	if _,ok := r.length();!ok{
+		return newError("range %s has too many elements",r.Inspect())
+	}
+
+	return r
+}
+
+//迭代器，每次调用next取得下一个值，ok为false表示已经结束
+type Iterator struct {
+	next func()(Object,bool)
+	stop func()Object //提前结束时的清理，可以为nil
+	done bool
+}
+
+func NewIterator(next func()(Object,bool))*Iterator{
+	return &Iterator{next:next}
+}
+
+func (it *Iterator)Type()ObjectType{
+	return ITERATOR_OBJ
+}
+func (it *Iterator)Inspect()string{
+	return "iterator"
+}
+
+//结束之后一直返回false
+func (it *Iterator)Next()(Object,bool){
+	if it.done{
+		return nil,false
+	}
+
+	value,ok := it.next()
+	if !ok{
+		it.done = true
+	}
+
+	return value,ok
+}
+
+//提前结束迭代，返回清理中的错误
+func (it *Iterator)Close()Object{
+	if it.done{
+		return nil
+	}
+	it.done = true
+
+	if it.stop != nil{
+		return it.stop()
+	}
+	return nil
+}
+
+//通道，发送的值先换成共享的拷贝(见shareValue)，任务之间不共享可变的值
+//所有通道由sched.mu保护，等待中的发送和接收排在通道的队列里，见concurrency.go
+type Channel struct {
+	size int
+	buffer []Object
+	closed bool
+	recvq []*channelWait
+	sendq []*channelWait
+}
+
+func NewChannel(size int)*Channel{
+	return &Channel{size:size}
+}
+
+func (c *Channel)Type()ObjectType{
+	return CHANNEL_OBJ
+}
+func (c *Channel)Inspect()string{
+	return fmt.Sprintf("channel(%d)",c.size)
+}
+
+//task表示是不是在spawn的任务中调用，见Environment.inTask
+func (c *Channel)Send(value Object,task bool)Object{
+	shared,err := shareValue(value)
+	if err != nil{
+		return err
+	}
+
+	result := selectChannels("send",[]channelOp{{c:c,send:true,value:shared}},true,task)
+	if result.err != nil{
+		return result.err
+	}
+	return NULL
+}
+
+//ok为false表示通道已经关闭并且取完了缓冲的值，会死锁时返回错误
+func (c *Channel)Recv(task bool)(Object,bool){
+	result := selectChannels("recv",[]channelOp{{c:c}},true,task)
+	if result.err != nil{
+		return result.err,true
+	}
+	return result.value,result.ok
+}
+
+//等待中的接收得到null，等待中的发送出错
+func (c *Channel)Close()Object{
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+
+	if c.closed{
+		return newError("close of closed channel")
+	}
+	c.closed = true
+
+	for _,w := range c.recvq{
+		w.fire(channelResult{index:w.index})
+	}
+	for _,w := range c.sendq{
+		w.fire(channelResult{index:w.index,err:newError("send on closed channel")})
+	}
+	c.recvq = nil
+	c.sendq = nil
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package evaluator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPersistentVector(t *testing.T){
+	sizes := []int{0,1,31,32,33,1024,1025,1057,40000}
+
+	for _,size := range sizes{
+		v := emptyVector
+		versions := []*pvector{}
+		for i := 0; i < size; i++{
+			versions = append(versions,v)
+			v = v.Append(&Integer{Value:int64(i)})
+		}
+
+		if v.Len() != size{
+			t.Fatalf("size %d: expected len %d,got=%d",size,size,v.Len())
+		}
+
+		for i := 0; i < size; i++{
+			if v.At(i).(*Integer).Value != int64(i){
+				t.Fatalf("size %d: At(%d) wrong,got=%s",size,i,v.At(i).Inspect())
+			}
+		}
+
+		//旧版本保持不变
+		for n,old := range versions{
+			if old.Len() != n{
+				t.Fatalf("size %d: version %d has len %d",size,n,old.Len())
+			}
+		}
+
+		count := 0
+		v.Each(func(i int, val Object) bool {
+			if val.(*Integer).Value != int64(i){
+				t.Fatalf("size %d: Each(%d) wrong,got=%s",size,i,val.Inspect())
+			}
+			count++
+			return true
+		})
+		if count != size{
+			t.Fatalf("size %d: Each visited %d elements",size,count)
+		}
+
+		if size == 0{
+			continue
+		}
+
+		for _,i := range []int{0,size/2,size-1}{
+			updated := v.Set(i,&StringObject{Value:"x"})
+			if updated.At(i).Inspect() != "x"{
+				t.Errorf("size %d: Set(%d) not applied",size,i)
+			}
+			if v.At(i).(*Integer).Value != int64(i){
+				t.Errorf("size %d: Set(%d) modified the old version",size,i)
+			}
+		}
+	}
+}
+
+func TestPersistentMap(t *testing.T){
+	m := emptyPmap
+	size := 5000
+
+	for i := 0; i < size; i++{
+		m = m.Set(&StringObject{Value:fmt.Sprintf("k%d",i)},&Integer{Value:int64(i)})
+	}
+
+	if m.Len() != size{
+		t.Fatalf("expected len %d,got=%d",size,m.Len())
+	}
+
+	before := m
+	for i := 0; i < size; i += 2{
+		m = m.Delete(&StringObject{Value:fmt.Sprintf("k%d",i)})
+	}
+	m = m.Set(&StringObject{Value:"k1"},&Integer{Value:-1})
+	m = m.Set(&StringObject{Value:"k0"},&Integer{Value:0})
+
+	if m.Len() != size/2+1{
+		t.Fatalf("expected len %d,got=%d",size/2+1,m.Len())
+	}
+	if before.Len() != size{
+		t.Fatalf("old version changed,len=%d",before.Len())
+	}
+	if v,_ := before.Get(&StringObject{Value:"k1"});v.(*Integer).Value != 1{
+		t.Fatalf("old version changed,k1=%s",v.Inspect())
+	}
+
+	//删除后重新插入的key排在最后，覆盖的key保留原位置
+	keys := []string{}
+	m.Each(func(key Hashable, value Object) bool {
+		keys = append(keys,key.Inspect())
+		return true
+	})
+	if keys[0] != "k1" || keys[len(keys)-1] != "k0"{
+		t.Errorf("unexpected order,first=%s last=%s",keys[0],keys[len(keys)-1])
+	}
+	if v,_ := m.Get(&StringObject{Value:"k1"});v.(*Integer).Value != -1{
+		t.Errorf("k1 not updated,got=%s",v.Inspect())
+	}
+	if _,ok := m.Get(&StringObject{Value:"k2"});ok{
+		t.Errorf("k2 not deleted")
+	}
+}
+
+func TestPersistentMapCollisions(t *testing.T){
+	//长度相同的字符串hash相同
+	m := &pmap{root:&hamtNode{},order:emptyVector,hash:func(key Hashable) uint64 {
+		return uint64(len(key.(*StringObject).Value))
+	}}
+	for _,k := range []string{"aa","bb","cc","d"}{
+		m = m.Set(&StringObject{Value:k},&StringObject{Value:k})
+	}
+
+	m = m.Delete(&StringObject{Value:"bb"})
+	if m.Len() != 3{
+		t.Fatalf("expected len 3,got=%d",m.Len())
+	}
+	for _,k := range []string{"aa","cc","d"}{
+		if v,ok := m.Get(&StringObject{Value:k});!ok || v.Inspect() != k{
+			t.Errorf("lookup %s failed",k)
+		}
+	}
+	if _,ok := m.Get(&StringObject{Value:"bb"});ok{
+		t.Errorf("bb not deleted")
+	}
+}
+
+func TestPersistentSharingAcrossGoroutines(t *testing.T){
+	base := NewArray(nil)
+	for i := 0; i < 1000; i++{
+		base.Push(&Integer{Value:int64(i)})
+	}
+	shared := base.Copy()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++{
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			local := shared
+			for i := 0; i < 100; i++{
+				local = local.Append(&Integer{Value:int64(g)})
+			}
+			if local.Len() != 1100 || local.At(1099).(*Integer).Value != int64(g){
+				t.Errorf("goroutine %d: update lost",g)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if shared.Len() != 1000 || shared.At(0).(*Integer).Value != 0{
+		t.Errorf("shared version changed")
+	}
+}
@@ -0,0 +1,331 @@
+package evaluator
+
+import "math/bits"
+
+//持久化hash map(HAMT)，保留插入顺序
+//每层用hash的5位选择子节点，节点只为存在的子节点分配空间；
+//完整hash相同的key放在同一个冲突节点里，再用keysEqual比较实际的值。
+//与pvector一样，修改返回新版本，旧版本不受影响
+
+const (
+	hamtBits = 5
+	hamtMask = 1 << hamtBits - 1
+	hamtMaxShift = 64
+)
+
+type pmapEntry struct {
+	key Hashable
+	value Object
+	hash uint64
+	seq int //在order中的位置
+}
+
+type hamtNode struct {
+	bitmap uint32
+	children []interface{} //*pmapEntry,*hamtNode或*collisionNode
+}
+
+type collisionNode struct {
+	hash uint64
+	entries []*pmapEntry
+}
+
+type pmap struct {
+	root *hamtNode
+	count int
+	//按插入顺序记录的entry，删除或覆盖后旧的entry留在这里，
+	//遍历时和root中的entry比较seq来跳过
+	order *pvector
+	//计算key的hash，nil时用hashOf；测试用它制造冲突，修改后的版本继承它
+	hash func(key Hashable)uint64
+}
+
+var emptyPmap = &pmap{root:&hamtNode{},order:emptyVector}
+
+func hashOf(key Hashable)uint64{
+	hk := key.HashKey()
+	return hk.Value ^ hashString(string(hk.Type))
+}
+
+func (m *pmap)hashOf(key Hashable)uint64{
+	if m.hash != nil{
+		return m.hash(key)
+	}
+	return hashOf(key)
+}
+
+func (m *pmap)Len()int{
+	return m.count
+}
+
+func (m *pmap)lookup(key Hashable)*pmapEntry{
+	return m.root.get(0,m.hashOf(key),key)
+}
+
+func (m *pmap)Get(key Hashable)(Object,bool){
+	entry := m.lookup(key)
+	if entry == nil{
+		return nil,false
+	}
+
+	return entry.value,true
+}
+
+//已存在的key保留原来的位置
+func (m *pmap)Set(key Hashable,value Object)*pmap{
+	hash := m.hashOf(key)
+
+	if existing := m.root.get(0,hash,key);existing != nil{
+		entry := &pmapEntry{key:key,value:value,hash:hash,seq:existing.seq}
+		root,_ := m.root.assoc(0,entry)
+		return &pmap{root:root,count:m.count,order:m.order,hash:m.hash}
+	}
+
+	entry := &pmapEntry{key:key,value:value,hash:hash,seq:m.order.Len()}
+	root,_ := m.root.assoc(0,entry)
+	return &pmap{root:root,count:m.count+1,order:m.order.Append(&orderRecord{entry}),hash:m.hash}
+}
+
+func (m *pmap)Delete(key Hashable)*pmap{
+	root,removed := m.root.dissoc(0,m.hashOf(key),key)
+	if !removed{
+		return m
+	}
+
+	deleted := &pmap{root:root,count:m.count-1,order:m.order,hash:m.hash}
+	//删除留下的记录太多时重建顺序
+	if deleted.order.Len() > 2 * deleted.count + vectorWidth{
+		return deleted.compact()
+	}
+
+	return deleted
+}
+
+func (m *pmap)live(record *orderRecord)*pmapEntry{
+	current := m.root.get(0,record.entry.hash,record.entry.key)
+	if current == nil || current.seq != record.entry.seq{
+		return nil
+	}
+
+	return current
+}
+
+//按插入顺序遍历，fn返回false时停止
+func (m *pmap)Each(fn func(key Hashable,value Object)bool){
+	m.order.Each(func(i int, val Object) bool {
+		entry := m.live(val.(*orderRecord))
+		if entry == nil{
+			return true
+		}
+
+		return fn(entry.key,entry.value)
+	})
+}
+
+func (m *pmap)compact()*pmap{
+	result := &pmap{root:&hamtNode{},order:emptyVector,hash:m.hash}
+	m.Each(func(key Hashable, value Object) bool {
+		result = result.Set(key,value)
+		return true
+	})
+
+	return result
+}
+
+//order中保存的记录，实现Object只是为了能放进pvector
+type orderRecord struct {
+	entry *pmapEntry
+}
+
+func (r *orderRecord)Type()ObjectType{
+	return "ORDER_RECORD"
+}
+func (r *orderRecord)Inspect()string{
+	return r.entry.key.Inspect()
+}
+
+func hamtIndex(hash uint64,shift uint)uint32{
+	return uint32(1) << ((hash >> shift) & hamtMask)
+}
+
+func (n *hamtNode)position(bit uint32)int{
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode)get(shift uint,hash uint64,key Hashable)*pmapEntry{
+	bit := hamtIndex(hash,shift)
+	if n.bitmap & bit == 0{
+		return nil
+	}
+
+	switch child := n.children[n.position(bit)].(type) {
+	case *pmapEntry:
+		if child.hash == hash && keysEqual(child.key,key){
+			return child
+		}
+		return nil
+	case *hamtNode:
+		return child.get(shift+hamtBits,hash,key)
+	case *collisionNode:
+		return child.get(hash,key)
+	}
+
+	return nil
+}
+
+//返回插入entry后的新节点，added表示是否新增了key
+func (n *hamtNode)assoc(shift uint,entry *pmapEntry)(*hamtNode,bool){
+	bit := hamtIndex(entry.hash,shift)
+	pos := n.position(bit)
+
+	if n.bitmap & bit == 0{
+		children := make([]interface{},len(n.children)+1)
+		copy(children,n.children[:pos])
+		children[pos] = entry
+		copy(children[pos+1:],n.children[pos:])
+
+		return &hamtNode{bitmap:n.bitmap | bit,children:children},true
+	}
+
+	var replacement interface{}
+	added := false
+
+	switch child := n.children[pos].(type) {
+	case *pmapEntry:
+		if child.hash == entry.hash && keysEqual(child.key,entry.key){
+			replacement = entry
+		}else{
+			replacement = mergeEntries(shift+hamtBits,child,entry)
+			added = true
+		}
+	case *hamtNode:
+		replacement,added = child.assoc(shift+hamtBits,entry)
+	case *collisionNode:
+		if child.hash == entry.hash{
+			replacement,added = child.assoc(entry)
+		}else{
+			//完整hash不同，拆成子节点
+			sub := &hamtNode{bitmap:hamtIndex(child.hash,shift+hamtBits),children:[]interface{}{child}}
+			replacement,added = sub.assoc(shift+hamtBits,entry)
+		}
+	}
+
+	children := make([]interface{},len(n.children))
+	copy(children,n.children)
+	children[pos] = replacement
+
+	return &hamtNode{bitmap:n.bitmap,children:children},added
+}
+
+func mergeEntries(shift uint,a,b *pmapEntry)interface{}{
+	if a.hash == b.hash || shift >= hamtMaxShift{
+		return &collisionNode{hash:a.hash,entries:[]*pmapEntry{a,b}}
+	}
+
+	node := &hamtNode{}
+	node,_ = node.assoc(shift,a)
+	node,_ = node.assoc(shift,b)
+
+	return node
+}
+
+//返回删除key后的新节点，节点变空时返回nil
+func (n *hamtNode)dissoc(shift uint,hash uint64,key Hashable)(*hamtNode,bool){
+	bit := hamtIndex(hash,shift)
+	if n.bitmap & bit == 0{
+		return n,false
+	}
+
+	pos := n.position(bit)
+	var replacement interface{}
+
+	switch child := n.children[pos].(type) {
+	case *pmapEntry:
+		if child.hash != hash || !keysEqual(child.key,key){
+			return n,false
+		}
+	case *hamtNode:
+		sub,removed := child.dissoc(shift+hamtBits,hash,key)
+		if !removed{
+			return n,false
+		}
+		if sub != nil{
+			replacement = sub
+		}
+	case *collisionNode:
+		entries,removed := child.dissoc(hash,key)
+		if !removed{
+			return n,false
+		}
+		switch len(entries) {
+		case 0:
+		case 1:
+			replacement = entries[0]
+		default:
+			replacement = &collisionNode{hash:child.hash,entries:entries}
+		}
+	}
+
+	if replacement != nil{
+		children := make([]interface{},len(n.children))
+		copy(children,n.children)
+		children[pos] = replacement
+
+		return &hamtNode{bitmap:n.bitmap,children:children},true
+	}
+
+	if len(n.children) == 1 && shift > 0{
+		return nil,true
+	}
+
+	children := make([]interface{},0,len(n.children)-1)
+	children = append(children,n.children[:pos]...)
+	children = append(children,n.children[pos+1:]...)
+
+	return &hamtNode{bitmap:n.bitmap &^ bit,children:children},true
+}
+
+func (c *collisionNode)get(hash uint64,key Hashable)*pmapEntry{
+	if c.hash != hash{
+		return nil
+	}
+
+	for _,entry := range c.entries{
+		if keysEqual(entry.key,key){
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func (c *collisionNode)assoc(entry *pmapEntry)(*collisionNode,bool){
+	entries := make([]*pmapEntry,len(c.entries),len(c.entries)+1)
+	copy(entries,c.entries)
+
+	for i,existing := range entries{
+		if keysEqual(existing.key,entry.key){
+			entries[i] = entry
+			return &collisionNode{hash:c.hash,entries:entries},false
+		}
+	}
+
+	return &collisionNode{hash:c.hash,entries:append(entries,entry)},true
+}
+
+func (c *collisionNode)dissoc(hash uint64,key Hashable)([]*pmapEntry,bool){
+	if c.hash != hash{
+		return c.entries,false
+	}
+
+	for i,entry := range c.entries{
+		if keysEqual(entry.key,key){
+			entries := make([]*pmapEntry,0,len(c.entries)-1)
+			entries = append(entries,c.entries[:i]...)
+			entries = append(entries,c.entries[i+1:]...)
+			return entries,true
+		}
+	}
+
+	return c.entries,false
+}
@@ -0,0 +1,52 @@
+package evaluator
+
+import (
+	"ast"
+	"io/fs"
+	"stdlib"
+	"strings"
+	"sync"
+)
+
+//标准库在第一次创建根环境时解析和求值，之后所有根环境共享同一个prelude
+var (
+	preludeOnce sync.Once
+	prelude *Environment
+)
+
+//包含标准库的根环境，用户的声明可以遮蔽标准库中的名字
+func NewRootEnvironment()*Environment{
+	preludeOnce.Do(func(){
+		prelude = loadPrelude(stdlib.FS,stdlib.Programs)
+	})
+
+	return NewEnclosedEnvironment(prelude)
+}
+
+//按文件名顺序加载fsys根目录下的每个模块，把导出的名字放进一个环境
+//programs中有预先解析好的语法树的模块不再解析源码
+//标准库随二进制发布，其中的错误是程序的bug，直接panic
+func loadPrelude(fsys fs.FS,programs map[string]*ast.Program)*Environment{
+	files,err := fs.Glob(fsys,"*" + ModuleExt)
+	if err != nil{
+		panic(err)
+	}
+
+	env := NewEnvironment()
+	loader := NewModuleLoader(fsys)
+	loader.prelude = true
+	loader.programs = programs
+	for _,file := range files{
+		result := loader.Load(strings.TrimSuffix(file,ModuleExt))
+
+		module,ok := result.(*Module)
+		if !ok{
+			panic("stdlib:" + result.Inspect())
+		}
+		for _,pair := range module.Exports.Pairs(){
+			env.SetConst(pair.Key.(*StringObject).Value,pair.Value)
+		}
+	}
+
+	return env
+}
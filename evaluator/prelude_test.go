@@ -0,0 +1,158 @@
+package evaluator
+
+import (
+	"ast"
+	"io/fs"
+	"lexer"
+	"parser"
+	"reflect"
+	"stdlib"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testEvalPrelude(t *testing.T,env *Environment,input string)Object{
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0{
+		t.Fatalf("parser has errors:%v",p.Errors())
+	}
+
+	return Eval(program,env)
+}
+
+func TestPrelude(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"identity(5)","5"},
+		{"constant(1)(2)","1"},
+		{"compose(fn(x){ x + 1 },fn(x){ x * 2 })(5)","11"},
+		{"pipe([fn(x){ x + 1 },fn(x){ x * 2 }])(5)","12"},
+		{"flip(fn(a,b){ a - b })(1,10)","9"},
+		{"partial(fn(a,b){ a - b },10)(1)","9"},
+		{"times(4,fn(i){ i * i })","[0,1,4,9]"},
+		{`pad_left("7",3,"0")`,"007"},
+		{`pad_right("ab",4,".")`,"ab.."},
+		{`center("ab",7,"*")`,"**ab***"},
+		{`capitalize("hello")`,"Hello"},
+		{`capitalize("")`,""},
+		{`lines("a\nb")`,"[a,b]"},
+		{"lines(\"a\nb\\nc\")","[a,b,c]"},
+		{`words("  a b  c")`,"[a,b,c]"},
+		{"sum([1,2,3])","6"},
+		{"product([2,3,4])","24"},
+		{"max([3,9,2])","9"},
+		{"min([3,9,2])","2"},
+		{"max([])","null"},
+		{"min([])","null"},
+		{"count([1,2,3,4],fn(x){ x > 2 })","2"},
+		{"find([1,2,3],fn(x){ x > 1 })","2"},
+		{"find([1,2,3],fn(x){ x > 5 })","null"},
+		{"take([1,2,3],2)","[1,2]"},
+		{"drop([1,2,3],2)","[3]"},
+		{"drop([1,2,3],5)","[]"},
+		{"chunk([1,2,3,4,5],2)","[[1,2],[3,4],[5]]"},
+		{"chunk([1,2,3],0)","ERROR:chunk: size must be positive,got 0"},
+		{"chunk([1,2,3],-2)","ERROR:chunk: size must be positive,got -2"},
+		{"chunk([],0)","ERROR:chunk: size must be positive,got 0"},
+		{"flatten([[1],[2,3],[]])","[1,2,3]"},
+		{`zip([1,2,3],["a","b"])`,"[(1,a),(2,b)]"},
+		{"uniq([1,2,1,3,2])","[1,2,3]"},
+		{`group_by(["a","bb","c"],len)`,"{1:[a,c],2:[bb]}"},
+		{"let sum = fn(a){ 0 }; sum([1,2])","0"},
+	}
+
+	for _,tt := range tests{
+		evaluated := testEvalPrelude(t,NewRootEnvironment(),tt.input)
+		if evaluated == nil || evaluated.Inspect() != tt.expected{
+			t.Errorf("%s: expected %q,got=%v",tt.input,tt.expected,evaluated)
+		}
+	}
+}
+
+func TestPrelude_Shared(t *testing.T){
+	a := NewRootEnvironment()
+	b := NewRootEnvironment()
+
+	if a.outer != b.outer{
+		t.Errorf("root environments should share the prelude")
+	}
+
+	testEvalPrelude(t,a,"let identity = 1;")
+	if evaluated := testEvalPrelude(t,b,"identity(2)");evaluated.Inspect() != "2"{
+		t.Errorf("shadowing in one root environment leaked into another,got %s",evaluated.Inspect())
+	}
+}
+
+func TestLoadPrelude(t *testing.T){
+	fsys := fstest.MapFS{
+		"a.monkey":{Data:[]byte(`let hidden = 1; export let double = fn(x){ x * 2 };`)},
+		"b.monkey":{Data:[]byte(`let a = import "a"; export let quad = fn(x){ a.double(a.double(x)) };`)},
+		"lib/c.monkey":{Data:[]byte(`export let nested = 1;`)},
+	}
+
+	env := loadPrelude(fsys,nil)
+	for _,name := range []string{"double","quad"}{
+		if _,ok := env.Get(name);!ok{
+			t.Errorf("expected %s in prelude",name)
+		}
+	}
+	for _,name := range []string{"hidden","a","nested"}{
+		if _,ok := env.Get(name);ok{
+			t.Errorf("unexpected %s in prelude",name)
+		}
+	}
+
+	defer func(){
+		if recover() == nil{
+			t.Errorf("expected a broken prelude to panic")
+		}
+	}()
+	loadPrelude(fstest.MapFS{"bad.monkey":{Data:[]byte(`let x = ;`)}},nil)
+}
+
+//有预编译的语法树的模块不解析源码
+func TestLoadPrelude_Precompiled(t *testing.T){
+	fsys := fstest.MapFS{
+		"a.monkey":{Data:[]byte(`let x = ;`)},
+		"b.monkey":{Data:[]byte(`export let fromSource = 2;`)},
+	}
+	p := parser.New(lexer.New(`export let precompiled = 1;`))
+	programs := map[string]*ast.Program{"a":p.ParseProgram()}
+
+	env := loadPrelude(fsys,programs)
+	for _,name := range []string{"precompiled","fromSource"}{
+		if _,ok := env.Get(name);!ok{
+			t.Errorf("expected %s in prelude",name)
+		}
+	}
+}
+
+//precompiled.go要和.monkey源码一致，修改标准库后需要运行go generate
+func TestPrelude_PrecompiledUpToDate(t *testing.T){
+	files,err := fs.Glob(stdlib.FS,"*" + ModuleExt)
+	if err != nil{
+		t.Fatal(err)
+	}
+	if len(files) != len(stdlib.Programs){
+		t.Errorf("expected %d precompiled modules,got %d",len(files),len(stdlib.Programs))
+	}
+
+	for _,file := range files{
+		src,err := fs.ReadFile(stdlib.FS,file)
+		if err != nil{
+			t.Fatal(err)
+		}
+		p := parser.New(lexer.New(string(src)))
+		program := p.ParseProgram()
+
+		name := strings.TrimSuffix(file,ModuleExt)
+		if !reflect.DeepEqual(program,stdlib.Programs[name]){
+			t.Errorf("stdlib %s is not precompiled,run go generate in stdlib",file)
+		}
+	}
+}
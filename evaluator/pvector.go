@@ -0,0 +1,178 @@
+package evaluator
+
+//持久化向量(vector trie)，32叉树加一个尾部缓冲
+//所有修改操作都返回新的向量，新旧版本共享未修改的节点，
+//旧版本始终有效，可以安全地在多个goroutine之间共享
+
+const (
+	vectorBits = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask = vectorWidth - 1
+)
+
+type vectorNode struct {
+	children [vectorWidth]interface{}
+}
+
+type pvector struct {
+	count int
+	shift uint
+	root *vectorNode
+	tail []Object
+}
+
+var emptyVector = &pvector{shift:vectorBits,root:&vectorNode{}}
+
+func newVector(elements []Object)*pvector{
+	v := emptyVector
+	for _,e := range elements{
+		v = v.Append(e)
+	}
+
+	return v
+}
+
+func (v *pvector)Len()int{
+	return v.count
+}
+
+//尾部缓冲之前的元素个数
+func (v *pvector)tailOffset()int{
+	if v.count < vectorWidth{
+		return 0
+	}
+
+	return ((v.count - 1) >> vectorBits) << vectorBits
+}
+
+//返回树中下标i所在的叶子节点，i必须小于tailOffset
+func (v *pvector)leafFor(i int)*vectorNode{
+	node := v.root
+	for level := v.shift; level > 0; level -= vectorBits{
+		node = node.children[(i >> level) & vectorMask].(*vectorNode)
+	}
+
+	return node
+}
+
+func (v *pvector)At(i int)Object{
+	if i >= v.tailOffset(){
+		return v.tail[i & vectorMask]
+	}
+
+	return v.leafFor(i).children[i & vectorMask].(Object)
+}
+
+func (v *pvector)Append(val Object)*pvector{
+	//尾部还有空间，只复制尾部
+	if v.count - v.tailOffset() < vectorWidth{
+		tail := make([]Object,len(v.tail)+1)
+		copy(tail,v.tail)
+		tail[len(v.tail)] = val
+
+		return &pvector{count:v.count+1,shift:v.shift,root:v.root,tail:tail}
+	}
+
+	//尾部已满，把它挂到树上
+	tailNode := &vectorNode{}
+	for i,e := range v.tail{
+		tailNode.children[i] = e
+	}
+
+	shift := v.shift
+	var root *vectorNode
+	if (v.count >> vectorBits) > (1 << v.shift){
+		//根节点已满，树增加一层
+		root = &vectorNode{}
+		root.children[0] = v.root
+		root.children[1] = newVectorPath(v.shift,tailNode)
+		shift += vectorBits
+	}else{
+		root = v.pushTail(v.shift,v.root,tailNode)
+	}
+
+	return &pvector{count:v.count+1,shift:shift,root:root,tail:[]Object{val}}
+}
+
+func (v *pvector)pushTail(level uint,parent *vectorNode,tailNode *vectorNode)*vectorNode{
+	idx := ((v.count - 1) >> level) & vectorMask
+	node := &vectorNode{children:parent.children}
+
+	var child *vectorNode
+	if level == vectorBits{
+		child = tailNode
+	}else if existing,ok := parent.children[idx].(*vectorNode);ok{
+		child = v.pushTail(level-vectorBits,existing,tailNode)
+	}else{
+		child = newVectorPath(level-vectorBits,tailNode)
+	}
+
+	node.children[idx] = child
+	return node
+}
+
+func newVectorPath(level uint,node *vectorNode)*vectorNode{
+	if level == 0{
+		return node
+	}
+
+	path := &vectorNode{}
+	path.children[0] = newVectorPath(level-vectorBits,node)
+	return path
+}
+
+//返回下标i被替换为val的新向量
+func (v *pvector)Set(i int,val Object)*pvector{
+	if i >= v.tailOffset(){
+		tail := make([]Object,len(v.tail))
+		copy(tail,v.tail)
+		tail[i & vectorMask] = val
+
+		return &pvector{count:v.count,shift:v.shift,root:v.root,tail:tail}
+	}
+
+	return &pvector{count:v.count,shift:v.shift,root:setInVector(v.shift,v.root,i,val),tail:v.tail}
+}
+
+func setInVector(level uint,node *vectorNode,i int,val Object)*vectorNode{
+	copied := &vectorNode{children:node.children}
+
+	if level == 0{
+		copied.children[i & vectorMask] = val
+	}else{
+		idx := (i >> level) & vectorMask
+		copied.children[idx] = setInVector(level-vectorBits,node.children[idx].(*vectorNode),i,val)
+	}
+
+	return copied
+}
+
+//按顺序逐个叶子遍历，fn返回false时停止
+func (v *pvector)Each(fn func(i int,val Object)bool){
+	offset := v.tailOffset()
+
+	for start := 0; start < offset; start += vectorWidth{
+		leaf := v.leafFor(start)
+		for j := 0; j < vectorWidth; j++{
+			if !fn(start+j,leaf.children[j].(Object)){
+				return
+			}
+		}
+	}
+
+	for j,e := range v.tail{
+		if !fn(offset+j,e){
+			return
+		}
+	}
+}
+
+func (v *pvector)Slice()[]Object{
+	elements := make([]Object,0,v.count)
+	v.Each(func(i int, val Object) bool {
+		elements = append(elements,val)
+		return true
+	})
+
+	return elements
+}
@@ -1,10 +1,17 @@
 package lexer
 
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//按utf8解码源码，position和readPosition是字节偏移
 type Lexer struct {
 	input string //源码
 	position int //position指向当前字符，即char所在的位置
 	readPosition int //readposition指向下一个字符
-	char byte
+	char rune
 }
 
 func New(input string)*Lexer{
@@ -17,14 +24,18 @@ func New(input string)*Lexer{
 }
 
 func (l *Lexer)readChar(){ //读取下一个字符
+	//到末尾后停在末尾，position不会超出input
 	if l.readPosition >= len(l.input) {
 		l.char = 0
-	}else{
-		l.char = l.input[l.readPosition]
+		l.position = len(l.input)
+		l.readPosition = len(l.input) + 1
+		return
 	}
 
+	char,size := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	l.char = char
 	l.position = l.readPosition
-	l.readPosition = l.readPosition + 1
+	l.readPosition = l.readPosition + size
 }
 
 func (l *Lexer)NextToken()Token{
@@ -48,13 +59,21 @@ func (l *Lexer)NextToken()Token{
 	case '<':
 		tok = NewToken(LT,'<')
 	case '-':
-		tok = NewToken(MINUS,'-')
+		if l.peekChar() == '>'{
+			tok = Token{ARROW,"->"}
+			l.readChar()
+		}else{
+			tok = NewToken(MINUS,'-')
+		}
 	case '/':
 		tok = NewToken(SLASH,'/')
 	case '=':
 		if l.peekChar() == '='{
 			tok = Token{EQ,"=="}
 			l.readChar()
+		}else if l.peekChar() == '>'{
+			tok = Token{FAT_ARROW,"=>"}
+			l.readChar()
 		}else{
 			tok =  NewToken(ASSIGN,'=')
 		}
@@ -78,7 +97,19 @@ func (l *Lexer)NextToken()Token{
 		tok.Type = EOF
 	case '"':// 字符串
 		tok.Type = STRING
-		tok.Value = l.readString()
+		start := l.position
+		value,interpolated,closed := l.readString()
+		if !closed{
+			//${没有对应的}，整段源码作为非法token
+			return Token{ILLEGAL,l.input[start:l.position]}
+		}
+		if interpolated{
+			//插值字符串在SplitInterpolation中处理转义
+			tok.Type = INTERP_STRING
+		}else{
+			value = unescape(value)
+		}
+		tok.Value = value
 	case '[':
 		tok.Type = LBRACKET
 		tok.Value = "["
@@ -89,6 +120,24 @@ func (l *Lexer)NextToken()Token{
 	case ':':
 		tok.Type = COLON
 		tok.Value = ":"
+	case '.':
+		if l.peekChar() != '.'{
+			tok = NewToken(DOT,'.')
+			break
+		}
+
+		//...、..=或..
+		l.readChar()
+		switch l.peekChar() {
+		case '.':
+			tok = Token{ELLIPSIS,"..."}
+			l.readChar()
+		case '=':
+			tok = Token{RANGE_INCLUSIVE,"..="}
+			l.readChar()
+		default:
+			tok = Token{RANGE,".."}
+		}
 
 	default:
 		if isLetter(l.char){
@@ -110,12 +159,13 @@ func (l *Lexer)NextToken()Token{
 	return tok
 }
 
-func (l *Lexer)peekChar()byte{
+func (l *Lexer)peekChar()rune{
 	if l.readPosition >= len(l.input){
 		return 0
 	}
 
-	return l.input[l.readPosition]
+	char,_ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return char
 }
 
 func (l *Lexer)readIdentifier()string{
@@ -127,39 +177,172 @@ func (l *Lexer)readIdentifier()string{
 	return l.input[position:l.position]
 }
 
+//整数，可以用1e9这样的指数形式
 func (l *Lexer)readDigit()string{
 	position := l.position
 	for isDigit(l.char){
 		l.readChar()
 	}
 
+	if l.char == 'e' && isDigit(l.peekChar()){
+		l.readChar()
+		for isDigit(l.char){
+			l.readChar()
+		}
+	}
+
 	return l.input[position:l.position]
 }
 
+//跳过空白和//开头的行注释
 func (l *Lexer)skipSpace(){
-	for l.char == ' ' || l.char == '\t' || l.char == '\n' || l.char == '\r'{
-		l.readChar()
+	for{
+		for l.char == ' ' || l.char == '\t' || l.char == '\n' || l.char == '\r'{
+			l.readChar()
+		}
+
+		if l.char != '/' || l.peekChar() != '/'{
+			return
+		}
+		for l.char != '\n' && l.char != 0{
+			l.readChar()
+		}
 	}
 }
 
 
-func isLetter(c byte)bool{
-	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c =='_'
+//标识符可以包含任意unicode字母，比如naïve、名前
+func isLetter(c rune)bool{
+	return unicode.IsLetter(c) || c =='_'
 }
 
-func isDigit(c byte)bool{
+func isDigit(c rune)bool{
 	return c >= '0' && c <= '9'
 }
 
-func (l *Lexer)readString()string{
+//读取字符串的内容，${}中可以有嵌套的字符串，interpolated表示是否有插值，
+//closed为false表示其中的${到末尾也没有对应的}
+func (l *Lexer)readString()(value string,interpolated bool,closed bool){
 	pos := l.position + 1
+	closed = true
 
 	for {
 		l.readChar()
-		if l.char == '"'{
+		if l.char == '"' || l.char == 0{
 			break
 		}
+		if l.char == '\\'{
+			l.readChar()
+			if l.char == 0{
+				break
+			}
+			continue
+		}
+		if l.char == '$' && l.peekChar() == '{'{
+			interpolated = true
+			l.readChar()
+			if !l.skipInterpolation(){
+				closed = false
+				break
+			}
+		}
+	}
+
+	return l.input[pos:l.position],interpolated,closed
+}
+
+//当前字符是${的{，跳到与之匹配的}，没有找到时返回false
+func (l *Lexer)skipInterpolation()bool{
+	depth := 1
+	for depth > 0{
+		l.readChar()
+		switch l.char {
+		case 0:
+			return false
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			if _,_,closed := l.readString();!closed{
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+//插值字符串中的一段，Expr为true时Value是${}中表达式的源码
+type StringPart struct {
+	Value string
+	Expr bool
+}
+
+//把INTERP_STRING的内容拆成文本和表达式
+func SplitInterpolation(s string)[]StringPart{
+	parts := []StringPart{}
+
+	l := New(s)
+	start := 0
+	for l.char != 0{
+		if l.char == '\\'{
+			l.readChar()
+		}else if l.char == '$' && l.peekChar() == '{'{
+			if l.position > start{
+				parts = append(parts,StringPart{Value:unescape(s[start:l.position])})
+			}
+
+			l.readChar()
+			exprStart := l.position + 1
+			l.skipInterpolation()
+			parts = append(parts,StringPart{Value:s[exprStart:l.position],Expr:true})
+			start = l.position + 1
+		}
+		l.readChar()
+	}
+
+	if start < len(s){
+		parts = append(parts,StringPart{Value:unescape(s[start:])})
+	}
+
+	return parts
+}
+
+//支持\n、\t、\"、\\和\$，其他的反斜杠原样保留
+func unescape(s string)string{
+	if !strings.ContainsRune(s,'\\'){
+		return s
+	}
+
+	var out strings.Builder
+	escaped := false
+	for _,c := range s{
+		if !escaped{
+			if c == '\\'{
+				escaped = true
+			}else{
+				out.WriteRune(c)
+			}
+			continue
+		}
+
+		escaped = false
+		switch c {
+		case 'n':
+			out.WriteRune('\n')
+		case 't':
+			out.WriteRune('\t')
+		case '"','\\','$':
+			out.WriteRune(c)
+		default:
+			out.WriteRune('\\')
+			out.WriteRune(c)
+		}
+	}
+	if escaped{
+		out.WriteRune('\\')
 	}
 
-	return l.input[pos:l.position]
+	return out.String()
 }
\ No newline at end of file
@@ -166,3 +166,410 @@ func TestLexer_NextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestLexer_StructTokens(t *testing.T) {
+	input := `struct Point { x, y }
+	p.x = 1;`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{STRUCT,"struct"},
+		{INDENT,"Point"},
+		{LBRACE,"{"},
+		{INDENT,"x"},
+		{COMMA,","},
+		{INDENT,"y"},
+		{RBRACE,"}"},
+		{INDENT,"p"},
+		{DOT,"."},
+		{INDENT,"x"},
+		{ASSIGN,"="},
+		{INT,"1"},
+		{SEMICOLON,";"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_MatchTokens(t *testing.T) {
+	input := `enum Shape { Circle(r) }
+	match (s) { Circle(r) if r == 1 => r, _ => 0 }`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{ENUM,"enum"},
+		{INDENT,"Shape"},
+		{LBRACE,"{"},
+		{INDENT,"Circle"},
+		{LPAREN,"("},
+		{INDENT,"r"},
+		{RPAREN,")"},
+		{RBRACE,"}"},
+		{MATCH,"match"},
+		{LPAREN,"("},
+		{INDENT,"s"},
+		{RPAREN,")"},
+		{LBRACE,"{"},
+		{INDENT,"Circle"},
+		{LPAREN,"("},
+		{INDENT,"r"},
+		{RPAREN,")"},
+		{IF,"if"},
+		{INDENT,"r"},
+		{EQ,"=="},
+		{INT,"1"},
+		{FAT_ARROW,"=>"},
+		{INDENT,"r"},
+		{COMMA,","},
+		{INDENT,"_"},
+		{FAT_ARROW,"=>"},
+		{INT,"0"},
+		{RBRACE,"}"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_Ellipsis(t *testing.T) {
+	input := `let [a, ...rest] = x.y; ..`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{LET,"let"},
+		{LBRACKET,"["},
+		{INDENT,"a"},
+		{COMMA,","},
+		{ELLIPSIS,"..."},
+		{INDENT,"rest"},
+		{RBRACKET,"]"},
+		{ASSIGN,"="},
+		{INDENT,"x"},
+		{DOT,"."},
+		{INDENT,"y"},
+		{SEMICOLON,";"},
+		{RANGE,".."},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_TypeAnnotations(t *testing.T) {
+	input := `fn(a: int) -> [int] { a - 1 }`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{FUNCTION,"fn"},
+		{LPAREN,"("},
+		{INDENT,"a"},
+		{COLON,":"},
+		{INDENT,"int"},
+		{RPAREN,")"},
+		{ARROW,"->"},
+		{LBRACKET,"["},
+		{INDENT,"int"},
+		{RBRACKET,"]"},
+		{LBRACE,"{"},
+		{INDENT,"a"},
+		{MINUS,"-"},
+		{INT,"1"},
+		{RBRACE,"}"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	input := `// 注释
+let x = 10 / 2; // 行尾注释
+//`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{LET,"let"},
+		{INDENT,"x"},
+		{ASSIGN,"="},
+		{INT,"10"},
+		{SLASH,"/"},
+		{INT,"2"},
+		{SEMICOLON,";"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_Interpolation(t *testing.T) {
+	input := `"plain" "a ${name}!" "${f("x}")}" "$ {x}"`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{STRING,"plain"},
+		{INTERP_STRING,"a ${name}!"},
+		{INTERP_STRING,`${f("x}")}`},
+		{STRING,"$ {x}"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestSplitInterpolation(t *testing.T) {
+	tests := []struct{
+		input string
+		expected []StringPart
+	}{
+		{"a ${name}!",[]StringPart{{"a ",false},{"name",true},{"!",false}}},
+		{"${a}${b}",[]StringPart{{"a",true},{"b",true}}},
+		{`${f("}")} ${ {"k":1} }`,[]StringPart{{`f("}")`,true},{" ",false},{` {"k":1} `,true}}},
+		{"${",[]StringPart{{"",true}}},
+		{`${x}\n\${y}`,[]StringPart{{"x",true},{"\n${y}",false}}},
+	}
+
+	for _,tt := range tests{
+		parts := SplitInterpolation(tt.input)
+		if len(parts) != len(tt.expected){
+			t.Fatalf("%q: expected %d parts,got %v",tt.input,len(tt.expected),parts)
+		}
+		for i,part := range parts{
+			if part != tt.expected[i]{
+				t.Errorf("%q: part %d expected %v,got %v",tt.input,i,tt.expected[i],part)
+			}
+		}
+	}
+}
+
+func TestLexer_UnterminatedString(t *testing.T) {
+	l := New(`"abc`)
+
+	tok := l.NextToken()
+	if tok.Type != STRING || tok.Value != "abc"{
+		t.Fatalf("expected STRING abc,got %q %q",tok.Type,tok.Value)
+	}
+	if tok = l.NextToken();tok.Type != EOF{
+		t.Fatalf("expected EOF,got %q",tok.Type)
+	}
+}
+
+func TestLexer_Escapes(t *testing.T) {
+	input := `"a\nb" "\t\"q\"\\" "\$x \q" "${x}\n\${y}" "a\"`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{STRING,"a\nb"},
+		{STRING,"\t\"q\"\\"},
+		{STRING,"$x \\q"},
+		{INTERP_STRING,`${x}\n\${y}`},
+		{STRING,"a\""},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+//${没有对应的}时整段是非法token，不会越界
+func TestLexer_UnterminatedInterpolation(t *testing.T) {
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`"${`,`"${`},
+		{`"${x`,`"${x`},
+		{`"a${"b`,`"a${"b`},
+		{`"${f("}")`,`"${f("}")`},
+		{`"${ {1}`,`"${ {1}`},
+	}
+
+	for _,tt := range tests{
+		l := New(tt.input)
+
+		tok := l.NextToken()
+		if tok.Type != ILLEGAL || tok.Value != tt.expected{
+			t.Fatalf("%s: expected ILLEGAL %q,got %q %q",tt.input,tt.expected,tok.Type,tok.Value)
+		}
+		if tok = l.NextToken();tok.Type != EOF{
+			t.Fatalf("%s: expected EOF,got %q",tt.input,tok.Type)
+		}
+	}
+
+	l := New(`"${x}`)
+	if tok := l.NextToken();tok.Type != INTERP_STRING || tok.Value != "${x}"{
+		t.Fatalf("expected INTERP_STRING ${x},got %q %q",tok.Type,tok.Value)
+	}
+}
+
+func TestLexer_Unicode(t *testing.T) {
+	input := `let naïve = "héllo"; 名前 + _x € δ`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{LET,"let"},
+		{INDENT,"naïve"},
+		{ASSIGN,"="},
+		{STRING,"héllo"},
+		{SEMICOLON,";"},
+		{INDENT,"名前"},
+		{PLUS,"+"},
+		{INDENT,"_x"},
+		{ILLEGAL,"€"},
+		{INDENT,"δ"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
+
+func TestLexer_Range(t *testing.T) {
+	input := `0..n 1..=10 a.b 1e9 2e x.. 5`
+
+	tests := []struct{
+		ExpectedType TokenType
+		ExpectedValue string
+	}{
+		{INT,"0"},
+		{RANGE,".."},
+		{INDENT,"n"},
+		{INT,"1"},
+		{RANGE_INCLUSIVE,"..="},
+		{INT,"10"},
+		{INDENT,"a"},
+		{DOT,"."},
+		{INDENT,"b"},
+		{INT,"1e9"},
+		{INT,"2"},
+		{INDENT,"e"},
+		{INDENT,"x"},
+		{RANGE,".."},
+		{INT,"5"},
+		{EOF,""},
+	}
+
+	l := New(input)
+	for i,test := range tests{
+		tok := l.NextToken()
+
+		if tok.Type != test.ExpectedType{
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, test.ExpectedType, tok.Type)
+		}
+		if tok.Value != test.ExpectedValue{
+			t.Fatalf("tests[%d] - value wrong. expected=%q, got=%q",
+				i, test.ExpectedValue, tok.Value)
+		}
+	}
+}
@@ -10,6 +10,7 @@ const(
 	INDENT = "INDENT"
 	INT = "INT"
 	STRING = "STRING"
+	INTERP_STRING = "INTERP_STRING" //包含${}的字符串
 
 	//operator
 	ASSIGN = "="
@@ -29,6 +30,12 @@ const(
 	COMMA = ","
 	SEMICOLON = ";"
 	COLON = ":"
+	DOT = "."
+	ELLIPSIS = "..."
+	RANGE = ".."
+	RANGE_INCLUSIVE = "..="
+	FAT_ARROW = "=>"
+	ARROW = "->"
 
 	LPAREN = "("
 	RPAREN = ")"
@@ -46,6 +53,16 @@ const(
 	IF = "if"
 	ELSE = "else"
 	RETURN = "return"
+	STRUCT = "struct"
+	ENUM = "enum"
+	MATCH = "match"
+	CONST = "const"
+	IMPORT = "import"
+	EXPORT = "export"
+	YIELD = "yield"
+	DEFER = "defer"
+	SPAWN = "spawn"
+	SELECT = "select"
 
 )
 
@@ -57,6 +74,16 @@ var keyWords = map[string]TokenType{
 	"if":IF,
 	"else":ELSE,
 	"return":RETURN,
+	"struct":STRUCT,
+	"enum":ENUM,
+	"match":MATCH,
+	"const":CONST,
+	"import":IMPORT,
+	"export":EXPORT,
+	"yield":YIELD,
+	"defer":DEFER,
+	"spawn":SPAWN,
+	"select":SELECT,
 
 }
 
@@ -65,7 +92,7 @@ type Token struct {
 	Value string
 }
 
-func NewToken(Type TokenType,Value byte) Token {
+func NewToken(Type TokenType,Value rune) Token {
 	return Token{Type:Type,Value:string(Value)}
 }
 
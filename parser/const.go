@@ -6,6 +6,8 @@ const (
 	_ int = iota
 
 	LOWEST
+	ASSIGN      // p.x = v
+	RANGE       // a..b
 	EQUALS      // ==
 	LESSGREATER // < >
 	SUM         // +
@@ -13,6 +15,7 @@ const (
 	PREFIX      //-X OR !X
 	CALL        //fn(X)
 	INDEX
+	DOT         // p.x
 )
 
 var (
@@ -27,5 +30,9 @@ var (
 		lexer.ASTERISK: PRODUCT,
 		lexer.LPAREN:CALL,
 		lexer.LBRACKET:INDEX,
+		lexer.DOT:DOT,
+		lexer.ASSIGN:ASSIGN,
+		lexer.RANGE:RANGE,
+		lexer.RANGE_INCLUSIVE:RANGE,
 	}
 )
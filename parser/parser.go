@@ -4,7 +4,10 @@ import "lexer"
 import (
 	"ast"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	)
 
 //parser的作用是将源码翻译成一个数据结构
@@ -23,10 +26,19 @@ type Parser struct {
 
 	prefixParseFns map[lexer.TokenType]prefixParsefn //是个tokentype
 	infixParseFns map[lexer.TokenType]infoxParsefn
+
+	//每个函数体是一个作用域，记录其中用const声明的名字
+	consts []map[string]bool
+	//正在解析的函数，yield和defer只能出现在函数中
+	functions []*ast.FunctionLiteral
+	//已经声明的结构体名字，只有它们后面的{是结构体字面量
+	structs map[string]bool
+	//正在解析let的模式，其中只有带括号或者带枚举名的名字是枚举分支
+	letPattern bool
 }
 
 func New(l *lexer.Lexer)*Parser{
-	p := &Parser{l:l,errors:[]string{}}
+	p := &Parser{l:l,errors:[]string{},consts:[]map[string]bool{{}},structs:map[string]bool{}}
 
 	p.nextToken()
 	p.nextToken()
@@ -35,6 +47,7 @@ func New(l *lexer.Lexer)*Parser{
 	p.registerPrefix(lexer.INDENT,p.parseIdentifier)
 	p.registerPrefix(lexer.INT,p.parseIntegerLiteral)
 	p.registerPrefix(lexer.STRING,p.parseStringLiteral)
+	p.registerPrefix(lexer.INTERP_STRING,p.parseInterpolatedString)
 
 	p.registerPrefix(lexer.MINUS,p.parsePrefixExpression)
 	p.registerPrefix(lexer.BANG,p.parsePrefixExpression)
@@ -47,6 +60,11 @@ func New(l *lexer.Lexer)*Parser{
 	p.registerPrefix(lexer.FUNCTION,p.parseFunctionLiteral)
 	p.registerPrefix(lexer.LBRACKET,p.parseArrayLiteral)
 	p.registerPrefix(lexer.LBRACE,p.parseHashLiteral)
+	p.registerPrefix(lexer.MATCH,p.parseMatchExpression)
+	p.registerPrefix(lexer.IMPORT,p.parseImportExpression)
+	p.registerPrefix(lexer.YIELD,p.parseYieldExpression)
+	p.registerPrefix(lexer.SPAWN,p.parseSpawnExpression)
+	p.registerPrefix(lexer.SELECT,p.parseSelectExpression)
 	//infix
 	p.infixParseFns = make(map[lexer.TokenType]infoxParsefn)
 	p.registerInfix(lexer.PLUS,p.parseInfixExpression)
@@ -59,18 +77,28 @@ func New(l *lexer.Lexer)*Parser{
 	p.registerInfix(lexer.GT,p.parseInfixExpression)
 	p.registerInfix(lexer.LPAREN,p.parseCallExpression)
 	p.registerInfix(lexer.LBRACKET,p.parseIndexExpression)
+	p.registerInfix(lexer.LBRACE,p.parseStructLiteral)
+	p.registerInfix(lexer.DOT,p.parseFieldExpression)
+	p.registerInfix(lexer.ASSIGN,p.parseAssignExpression)
+	p.registerInfix(lexer.RANGE,p.parseRangeExpression)
+	p.registerInfix(lexer.RANGE_INCLUSIVE,p.parseRangeExpression)
 	return p
 }
 
+//{}是空hash，{k:v}是hash，{a,b}是集合
 func (p *Parser)parseHashLiteral()ast.Expression{
 	hash := &ast.HashLiteral{Token:p.curToken}
 
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	hash.Pairs = []ast.HashLiteralPair{}
 
 	for !p.peekTokenis(lexer.RBRACE){
 		p.nextToken()
 		key := p.parseExpression(LOWEST)
 
+		if len(hash.Pairs) == 0 && !p.peekTokenis(lexer.COLON){
+			return p.parseSetLiteral(hash.Token,key)
+		}
+
 		if !p.expectPeek(lexer.COLON){
 			return nil
 		}
@@ -78,7 +106,7 @@ func (p *Parser)parseHashLiteral()ast.Expression{
 		p.nextToken()
 
 		value := p.parseExpression(LOWEST)
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs,ast.HashLiteralPair{Key:key,Value:value})
 
 		if !p.peekTokenis(lexer.RBRACE) && !p.expectPeek(lexer.COMMA){
 			return nil
@@ -92,12 +120,36 @@ func (p *Parser)parseHashLiteral()ast.Expression{
 	return hash
 }
 
+func (p *Parser)parseSetLiteral(tok lexer.Token,first ast.Expression)ast.Expression{
+	set := &ast.SetLiteral{Token:tok,Element:[]ast.Expression{first}}
+
+	for p.peekTokenis(lexer.COMMA){
+		p.nextToken()
+		p.nextToken()
+		set.Element = append(set.Element,p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return set
+}
+
 func (p *Parser)parseIndexExpression(array ast.Expression)ast.Expression{
-	idxExp := &ast.IndexExpression{}
+	idxExp := &ast.IndexExpression{Token:p.curToken}
 	idxExp.Left = array
 
 	p.nextToken()
+	if p.curTokenis(lexer.COLON){
+		return p.parseSliceExpression(idxExp.Token,array,nil)
+	}
+
 	idxExp.Index = p.parseExpression(LOWEST)
+	if p.peekTokenis(lexer.COLON){
+		p.nextToken()
+		return p.parseSliceExpression(idxExp.Token,array,idxExp.Index)
+	}
 
 	if !p.peekTokenis(lexer.RBRACKET){
 		return nil
@@ -107,6 +159,34 @@ func (p *Parser)parseIndexExpression(array ast.Expression)ast.Expression{
 	return idxExp
 }
 
+//a..b不包含b，a..=b包含b
+func (p *Parser)parseRangeExpression(start ast.Expression)ast.Expression{
+	exp := &ast.RangeExpression{Token:p.curToken,Start:start}
+	exp.Inclusive = p.curTokenis(lexer.RANGE_INCLUSIVE)
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	exp.End = p.parseExpression(precedence)
+
+	return exp
+}
+
+//x[a:b]、x[:b]、x[a:]，当前token是冒号
+func (p *Parser)parseSliceExpression(token lexer.Token,left ast.Expression,low ast.Expression)ast.Expression{
+	exp := &ast.SliceExpression{Token:token,Left:left,Low:low}
+
+	if !p.peekTokenis(lexer.RBRACKET){
+		p.nextToken()
+		exp.High = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(lexer.RBRACKET){
+		return nil
+	}
+
+	return exp
+}
+
 func (p *Parser)parseArrayLiteral()ast.Expression{
 	array := &ast.ArrayLiteral{}
 
@@ -143,6 +223,24 @@ func (p *Parser)Errors()[]string{
 	return p.errors
 }
 
+//在别处声明的结构体，比如REPL之前输入的行或者import得到的，让P{...}按结构体字面量解析
+func (p *Parser)DeclareStructs(names ...string){
+	for _,name := range names{
+		p.structs[name] = true
+	}
+}
+
+//解析过程中声明的和DeclareStructs加入的结构体名字
+func (p *Parser)Structs()[]string{
+	names := []string{}
+	for name := range p.structs{
+		names = append(names,name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func (p *Parser)peekError(t lexer.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s,got %s instead", t, p.peekToken.Type)
 
@@ -174,17 +272,24 @@ func (p *Parser)ParseProgram()*ast.Program{
 
 func (p *Parser)ParseStatement()ast.Statement{
 	switch p.curToken.Type {
-	case lexer.LET:
+	case lexer.LET,lexer.CONST:
 		return p.ParseLetStatement()
 	case lexer.RETURN:
 		return p.ParseReturnStatement()
+	case lexer.STRUCT:
+		return p.parseStructStatement()
+	case lexer.ENUM:
+		return p.parseEnumStatement()
+	case lexer.EXPORT:
+		return p.parseExportStatement()
+	case lexer.DEFER:
+		return p.parseDeferStatement()
 	default:
 		return p.parseExpressionStatement()
 	//	msg := fmt.Sprintf("invalid statement")
 	//	p.errors = append(p.errors,msg)
 
 	}
-	return nil
 }
 
 func (p *Parser)ParseReturnStatement()*ast.ReturnStatement {
@@ -194,9 +299,11 @@ func (p *Parser)ParseReturnStatement()*ast.ReturnStatement {
 
 	if !p.curTokenis(lexer.SEMICOLON){
 		stmt.ReturnValue = p.parseExpression(LOWEST)
-	}
 
-	p.nextToken()
+		if p.peekTokenis(lexer.SEMICOLON){
+			p.nextToken()
+		}
+	}
 
 	return stmt
 }
@@ -204,29 +311,76 @@ func (p *Parser)ParseReturnStatement()*ast.ReturnStatement {
 func (p *Parser)ParseLetStatement()*ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
 
-	if !p.expectPeek(lexer.INDENT) {
-		return nil
+	//let [a,b] = v、let {a} = v和let (a,b) = v是解构
+	if p.peekTokenis(lexer.LBRACKET) || p.peekTokenis(lexer.LBRACE) || p.peekTokenis(lexer.LPAREN){
+		p.nextToken()
+		p.letPattern = true
+		stmt.Pattern = p.parsePattern()
+		p.letPattern = false
+		if stmt.Pattern == nil{
+			return nil
+		}
+	}else{
+		if !p.expectPeek(lexer.INDENT) {
+			return nil
+		}
+
+		stmt.Name = &ast.Indetifier{Token: p.curToken, Value: p.curToken.Value}
 	}
 
-	stmt.Name = &ast.Indetifier{Token: p.curToken, Value: p.curToken.Value}
+	var ok bool
+	if stmt.Type,ok = p.parseTypeAnnotation();!ok{
+		return nil
+	}
 
 	if !p.expectPeek(lexer.ASSIGN){
 		return nil
 	}
 
+	if !p.declare(stmt){
+		return nil
+	}
+
 	p.nextToken()
 	stmt.Value = p.parseExpression(LOWEST)
 
-	p.nextToken()
-	for p.curTokenis(lexer.SEMICOLON){
+	if p.peekTokenis(lexer.SEMICOLON){
 		p.nextToken()
 	}
 
-
 	return stmt
 
 }
 
+//同一作用域里的const不能被let或const重新声明
+func (p *Parser)declare(stmt *ast.LetStatement)bool{
+	scope := p.consts[len(p.consts)-1]
+
+	for _,name := range stmt.Names(){
+		if scope[name.Value]{
+			msg := fmt.Sprintf("cannot redeclare const %s",name.Value)
+			p.errors = append(p.errors,msg)
+			return false
+		}
+	}
+
+	if stmt.IsConst(){
+		for _,name := range stmt.Names(){
+			scope[name.Value] = true
+		}
+	}
+
+	return true
+}
+
+func (p *Parser)enterScope(){
+	p.consts = append(p.consts,map[string]bool{})
+}
+
+func (p *Parser)leaveScope(){
+	p.consts = p.consts[:len(p.consts)-1]
+}
+
 func (p *Parser)curTokenis(t lexer.TokenType)bool{
 	return p.curToken.Type == t
 }
@@ -272,7 +426,8 @@ func (p *Parser)parseExpression(precedence int)ast.Expression{
 
 	leftExp := prefix()
 
-	for !p.peekTokenis(lexer.SEMICOLON) &&
+	//左边已经出错，错误已经记录
+	for leftExp != nil && !p.peekTokenis(lexer.SEMICOLON) &&
 		precedence < p.peekPrecedence(){
 			infix := p.infixParseFns[p.peekToken.Type]
 			if infix == nil{
@@ -295,10 +450,36 @@ func (p *Parser)parseIdentifier()ast.Expression{
 	}
 }
 
+//1e9是1后面9个0，超出int64时报错
+func parseInt(s string)(int64,error){
+	mantissa,exponent := s,"0"
+	if i := strings.IndexByte(s,'e');i >= 0{
+		mantissa,exponent = s[:i],s[i+1:]
+	}
+
+	value,err := strconv.ParseInt(mantissa,10,64)
+	if err != nil{
+		return 0,err
+	}
+	exp,err := strconv.Atoi(exponent)
+	if err != nil{
+		return 0,err
+	}
+
+	for ; exp > 0; exp--{
+		if value > math.MaxInt64 / 10{
+			return 0,strconv.ErrRange
+		}
+		value *= 10
+	}
+
+	return value,nil
+}
+
 func (p *Parser)parseIntegerLiteral()ast.Expression{
 	lit := &ast.IntergerLiteral{Token:p.curToken}
 
-	value,err := strconv.ParseInt(p.curToken.Value,10,64)
+	value,err := parseInt(p.curToken.Value)
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer",
 			p.curToken.Value)
@@ -313,13 +494,47 @@ func (p *Parser)parseIntegerLiteral()ast.Expression{
 }
 
 func (p *Parser)parseStringLiteral()ast.Expression{
-	lit := &ast.StringLiteral{}
+	lit := &ast.StringLiteral{Token:p.curToken}
 
 	lit.Value = p.curToken.Value
 
 	return lit
 }
 
+//${}中的每个表达式用单独的parser解析
+func (p *Parser)parseInterpolatedString()ast.Expression{
+	exp := &ast.InterpolatedString{Token:p.curToken}
+
+	for _,part := range lexer.SplitInterpolation(p.curToken.Value){
+		if !part.Expr{
+			text := lexer.Token{Type:lexer.STRING,Value:part.Value}
+			exp.Parts = append(exp.Parts,&ast.StringLiteral{Token:text,Value:part.Value})
+			continue
+		}
+
+		sub := New(lexer.New(part.Value))
+		if sub.curTokenis(lexer.EOF){
+			p.errors = append(p.errors,"empty interpolation in string")
+			return nil
+		}
+
+		inner := sub.parseExpression(LOWEST)
+		if len(sub.errors) == 0 && !sub.peekTokenis(lexer.EOF){
+			sub.errors = append(sub.errors,fmt.Sprintf("unexpected %s",sub.peekToken.Value))
+		}
+		for _,err := range sub.errors{
+			p.errors = append(p.errors,fmt.Sprintf("invalid interpolation ${%s}:%s",part.Value,err))
+		}
+		if len(sub.errors) != 0{
+			return nil
+		}
+
+		exp.Parts = append(exp.Parts,inner)
+	}
+
+	return exp
+}
+
 func (p *Parser)parsePrefixExpression()ast.Expression{
 	expression := &ast.PrefixExpression{
 		Token:p.curToken,
@@ -351,11 +566,22 @@ func (p *Parser)parseInfixExpression(left ast.Expression)ast.Expression{
 func (p *Parser)noPrefixParseFnError(t lexer.Token){
 	msg := fmt.Sprintf("no prefix parse function for" +
 		" %s found ",t.Type)
+	if t.Type == lexer.ILLEGAL{
+		msg = fmt.Sprintf("illegal token %s",t.Value)
+	}
 
 	p.errors = append(p.errors,msg)
 }
 
 func (p *Parser)peekPrecedence()int{
+	//P{x: 1}要求P是已知的结构体，其他表达式后面的{不属于这个表达式
+	if p.peekTokenis(lexer.LBRACE){
+		if p.curTokenis(lexer.INDENT) && p.structs[p.curToken.Value]{
+			return CALL
+		}
+		return LOWEST
+	}
+
 	if p,ok := precedence[p.peekToken.Type];ok{
 		return p
 	}
@@ -378,16 +604,43 @@ func (p *Parser)parseBoolean()ast.Expression{
 	}
 }
 
+//(x)是分组，()、(x,)和(x,y)是元组
 func (p *Parser)parseGroupExpression()ast.Expression{
+	tuple := &ast.TupleLiteral{Token:p.curToken,Element:[]ast.Expression{}}
+
+	if p.peekTokenis(lexer.RPAREN){
+		p.nextToken()
+		return tuple
+	}
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
 
+	if !p.peekTokenis(lexer.COMMA){
+		if !p.expectPeek(lexer.RPAREN){
+			return nil
+		}
+
+		return exp
+	}
+
+	tuple.Element = append(tuple.Element,exp)
+	for p.peekTokenis(lexer.COMMA){
+		p.nextToken()
+		if p.peekTokenis(lexer.RPAREN){
+			break
+		}
+
+		p.nextToken()
+		tuple.Element = append(tuple.Element,p.parseExpression(LOWEST))
+	}
+
 	if !p.expectPeek(lexer.RPAREN){
 		return nil
 	}
 
-	return exp
+	return tuple
 }
 
 func (p *Parser)parseIfExpression()ast.Expression{
@@ -446,47 +699,81 @@ func (p *Parser)parseFunctionLiteral()ast.Expression{
 		Token:p.curToken,
 	}
 
+	//fn*(...){...}是生成器
+	if p.peekTokenis(lexer.ASTERISK){
+		p.nextToken()
+		function.IsGenerator = true
+	}
+
 	if !p.expectPeek(lexer.LPAREN){
 		return nil
 	}
 
-	function.Parameters = p.parseFunctionParameters()
+	function.Parameters,function.ParameterTypes = p.parseFunctionParameters()
+	if function.Parameters == nil{
+		return nil
+	}
+
+	if p.peekTokenis(lexer.ARROW){
+		p.nextToken()
+		p.nextToken()
+		if function.ReturnType = p.parseType();function.ReturnType == nil{
+			return nil
+		}
+	}
 
 	if !p.expectPeek(lexer.LBRACE){
 		return nil
 	}
 
+	p.enterScope()
+	p.functions = append(p.functions,function)
 	function.Body = p.parseBlockStatement()
+	p.functions = p.functions[:len(p.functions)-1]
+	p.leaveScope()
 
 	return function
 }
 
-func (p *Parser)parseFunctionParameters()[]*ast.Indetifier{
+//参数可以带类型标注，a: int
+func (p *Parser)parseFunctionParameters()([]*ast.Indetifier,[]ast.TypeExpr){
 
 	identifiers := []*ast.Indetifier{}
+	types := []ast.TypeExpr{}
 
 	if p.peekTokenis(lexer.RPAREN){
 		p.nextToken()
-		return identifiers
+		return identifiers,types
 	}
 
 	p.nextToken()
 	ident := &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+	t,ok := p.parseTypeAnnotation()
+	if !ok{
+		return nil,nil
+	}
 
 	identifiers = append(identifiers,ident)
+	types = append(types,t)
 	for p.peekTokenis(lexer.COMMA){
 		p.nextToken()
 		p.nextToken()
 
 		ident := &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+		t,ok := p.parseTypeAnnotation()
+		if !ok{
+			return nil,nil
+		}
+
 		identifiers = append(identifiers,ident)
+		types = append(types,t)
 	}
 
 	if !p.expectPeek(lexer.RPAREN){
-		return nil
+		return nil,nil
 	}
 
-	return identifiers
+	return identifiers,types
 }
 
 func (p *Parser)parseCallExpression(function ast.Expression)ast.Expression{
@@ -523,4 +810,446 @@ func (p *Parser)parseCallArgument()[]ast.Expression{
 	}
 
 	return args
-}
\ No newline at end of file
+}
+//struct Point { x, y }
+func (p *Parser)parseStructStatement()ast.Statement{
+	stmt := &ast.StructStatement{Token:p.curToken}
+
+	if !p.expectPeek(lexer.INDENT){
+		return nil
+	}
+	stmt.Name = &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+	p.structs[stmt.Name.Value] = true
+
+	if !p.expectPeek(lexer.LBRACE){
+		return nil
+	}
+
+	stmt.Fields = []*ast.Indetifier{}
+	for !p.peekTokenis(lexer.RBRACE){
+		if !p.expectPeek(lexer.INDENT){
+			return nil
+		}
+		stmt.Fields = append(stmt.Fields,&ast.Indetifier{Token:p.curToken,Value:p.curToken.Value})
+
+		if !p.peekTokenis(lexer.RBRACE) && !p.expectPeek(lexer.COMMA){
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	if p.peekTokenis(lexer.SEMICOLON){
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+//Point{x:1,y:2}，只有标识符后面的{才是结构体构造
+func (p *Parser)parseStructLiteral(name ast.Expression)ast.Expression{
+	ident,ok := name.(*ast.Indetifier)
+	if !ok{
+		msg := fmt.Sprintf("unexpected { after %s",name.String())
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+
+	lit := &ast.StructLiteral{Token:p.curToken,Name:ident}
+	lit.Fields = []ast.StructLiteralField{}
+
+	for !p.peekTokenis(lexer.RBRACE){
+		if !p.expectPeek(lexer.INDENT){
+			return nil
+		}
+		field := &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+
+		if !p.expectPeek(lexer.COLON){
+			return nil
+		}
+		p.nextToken()
+
+		value := p.parseExpression(LOWEST)
+		lit.Fields = append(lit.Fields,ast.StructLiteralField{Name:field,Value:value})
+
+		if !p.peekTokenis(lexer.RBRACE) && !p.expectPeek(lexer.COMMA){
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return lit
+}
+
+//p.x是字段访问，p.x(args)是方法调用
+func (p *Parser)parseFieldExpression(left ast.Expression)ast.Expression{
+	exp := &ast.FieldExpression{Token:p.curToken,Left:left}
+
+	if !p.expectPeek(lexer.INDENT){
+		return nil
+	}
+	exp.Field = &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+
+	if p.peekTokenis(lexer.LPAREN){
+		p.nextToken()
+
+		return &ast.MethodCallExpression{
+			Token:exp.Token,
+			Receiver:left,
+			Method:exp.Field,
+			Arguments:p.parseCallArgument(),
+		}
+	}
+
+	return exp
+}
+
+//赋值是右结合的，a.x = b.y = 1
+func (p *Parser)parseAssignExpression(target ast.Expression)ast.Expression{
+	exp := &ast.AssignExpression{Token:p.curToken,Target:target}
+
+	if _,ok := target.(*ast.FieldExpression);!ok{
+		msg := fmt.Sprintf("invalid assignment target %s",target.String())
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+
+	return exp
+}
+
+//enum Shape { Circle(r), Rect(w, h), Empty }
+func (p *Parser)parseEnumStatement()ast.Statement{
+	stmt := &ast.EnumStatement{Token:p.curToken}
+
+	if !p.expectPeek(lexer.INDENT){
+		return nil
+	}
+	stmt.Name = &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+
+	if !p.expectPeek(lexer.LBRACE){
+		return nil
+	}
+
+	stmt.Variants = []*ast.EnumVariant{}
+	for !p.peekTokenis(lexer.RBRACE){
+		if !p.expectPeek(lexer.INDENT){
+			return nil
+		}
+		variant := &ast.EnumVariant{Name:&ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}}
+
+		if p.peekTokenis(lexer.LPAREN){
+			p.nextToken()
+			fields,types := p.parseFunctionParameters()
+			if fields == nil{
+				return nil
+			}
+			for _,t := range types{
+				if t != nil{
+					p.errors = append(p.errors,"enum fields cannot have type annotations")
+					return nil
+				}
+			}
+			variant.Fields = fields
+		}
+		stmt.Variants = append(stmt.Variants,variant)
+
+		if !p.peekTokenis(lexer.RBRACE) && !p.expectPeek(lexer.COMMA){
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	if p.peekTokenis(lexer.SEMICOLON){
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+//export后面只能是let、const、struct或enum声明
+func (p *Parser)parseExportStatement()ast.Statement{
+	stmt := &ast.ExportStatement{Token:p.curToken}
+	if len(p.consts) > 1{
+		p.errors = append(p.errors,"export is only allowed at the top level")
+		return nil
+	}
+
+	p.nextToken()
+	switch p.curToken.Type {
+	case lexer.LET,lexer.CONST:
+		let := p.ParseLetStatement()
+		if let == nil{
+			return nil
+		}
+		stmt.Statement = let
+	case lexer.STRUCT:
+		stmt.Statement = p.parseStructStatement()
+	case lexer.ENUM:
+		stmt.Statement = p.parseEnumStatement()
+	default:
+		msg := fmt.Sprintf("cannot export %s",p.curToken.Value)
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+
+	if stmt.Statement == nil{
+		return nil
+	}
+	return stmt
+}
+
+//yield x或者单独的yield，外层要有fn*声明的生成器
+//生成器中的回调和递归的辅助函数也可以yield，值交给外层的生成器
+func (p *Parser)parseYieldExpression()ast.Expression{
+	exp := &ast.YieldExpression{Token:p.curToken}
+
+	inGenerator := false
+	for _,fn := range p.functions{
+		inGenerator = inGenerator || fn.IsGenerator
+	}
+	if !inGenerator{
+		p.errors = append(p.errors,"yield outside generator")
+		return nil
+	}
+
+	if p.peekTokenis(lexer.SEMICOLON) || p.peekTokenis(lexer.RBRACE) || p.peekTokenis(lexer.EOF){
+		return exp
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+
+	return exp
+}
+
+//defer后面是一个表达式，函数返回时求值
+func (p *Parser)parseDeferStatement()ast.Statement{
+	stmt := &ast.DeferStatement{Token:p.curToken}
+
+	if len(p.functions) == 0{
+		p.errors = append(p.errors,"defer outside function")
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Call = p.parseExpression(LOWEST)
+	if stmt.Call == nil{
+		return nil
+	}
+
+	if p.peekTokenis(lexer.SEMICOLON){
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+//import "path/to/mod"
+func (p *Parser)parseImportExpression()ast.Expression{
+	exp := &ast.ImportExpression{Token:p.curToken}
+
+	if !p.expectPeek(lexer.STRING){
+		return nil
+	}
+	exp.Path = &ast.StringLiteral{Token:p.curToken,Value:p.curToken.Value}
+
+	return exp
+}
+
+//match (x) { pattern [if guard] => body, ... }
+//body可以是表达式或者{}语句块，分支之间的逗号可以省略
+func (p *Parser)parseMatchExpression()ast.Expression{
+	exp := &ast.MatchExpression{Token:p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN){
+		return nil
+	}
+
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.RPAREN){
+		return nil
+	}
+
+	if !p.expectPeek(lexer.LBRACE){
+		return nil
+	}
+
+	exp.Arms = []*ast.MatchArm{}
+	for !p.peekTokenis(lexer.RBRACE){
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil{
+			return nil
+		}
+		exp.Arms = append(exp.Arms,arm)
+
+		if p.peekTokenis(lexer.COMMA){
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser)parseMatchArm()*ast.MatchArm{
+	arm := &ast.MatchArm{Pattern:p.parsePattern()}
+	if arm.Pattern == nil{
+		return nil
+	}
+
+	if p.peekTokenis(lexer.IF){
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(lexer.FAT_ARROW){
+		return nil
+	}
+
+	p.nextToken()
+	arm.Body = p.parseArmBody()
+
+	return arm
+}
+
+//=>后面的语句块或者表达式，match和select的分支共用
+func (p *Parser)parseArmBody()*ast.BlockStatement{
+	if p.curTokenis(lexer.LBRACE){
+		//分支有自己的环境
+		p.enterScope()
+		body := p.parseBlockStatement()
+		p.leaveScope()
+		return body
+	}
+
+	//表达式包装成只有一条语句的语句块
+	tok := p.curToken
+	body := &ast.ExpressionStatement{Token:tok,Expression:p.parseExpression(LOWEST)}
+	return &ast.BlockStatement{Token:tok,Statements:[]ast.Statement{body}}
+}
+
+//spawn后面是调用或者没有参数的函数
+func (p *Parser)parseSpawnExpression()ast.Expression{
+	exp := &ast.SpawnExpression{Token:p.curToken}
+
+	p.nextToken()
+	if exp.Call = p.parseExpression(PREFIX);exp.Call == nil{
+		return nil
+	}
+
+	return exp
+}
+
+//select { v = recv(c) => ..., send(c,x) => ..., _ => ... }
+func (p *Parser)parseSelectExpression()ast.Expression{
+	exp := &ast.SelectExpression{Token:p.curToken}
+
+	if !p.expectPeek(lexer.LBRACE){
+		return nil
+	}
+
+	exp.Cases = []*ast.SelectCase{}
+	hasDefault := false
+	for !p.peekTokenis(lexer.RBRACE){
+		p.nextToken()
+
+		c := p.parseSelectCase()
+		if c == nil{
+			return nil
+		}
+		if c.Channel == nil{
+			if hasDefault{
+				p.errors = append(p.errors,"multiple defaults in select")
+				return nil
+			}
+			hasDefault = true
+		}
+		exp.Cases = append(exp.Cases,c)
+
+		if p.peekTokenis(lexer.COMMA){
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser)parseSelectCase()*ast.SelectCase{
+	c := &ast.SelectCase{Token:p.curToken}
+
+	if p.curTokenis(lexer.INDENT) && p.peekTokenis(lexer.ASSIGN){
+		c.Name = &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+		p.nextToken()
+		p.nextToken()
+		c.Token = p.curToken
+	}
+
+	switch {
+	case p.curTokenis(lexer.INDENT) && p.curToken.Value == "recv":
+		args := p.parseSelectArguments(1)
+		if args == nil{
+			return nil
+		}
+		c.Channel = args[0]
+	case p.curTokenis(lexer.INDENT) && p.curToken.Value == "send" && c.Name == nil:
+		args := p.parseSelectArguments(2)
+		if args == nil{
+			return nil
+		}
+		c.Channel,c.Value = args[0],args[1]
+	case p.curTokenis(lexer.INDENT) && p.curToken.Value == "_" && c.Name == nil:
+	default:
+		msg := fmt.Sprintf("expected recv,send or _ in select,got %s",p.curToken.Value)
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+
+	if !p.expectPeek(lexer.FAT_ARROW){
+		return nil
+	}
+
+	p.nextToken()
+	c.Body = p.parseArmBody()
+
+	return c
+}
+
+//select分支中recv和send的参数
+func (p *Parser)parseSelectArguments(want int)[]ast.Expression{
+	name := p.curToken.Value
+	if !p.expectPeek(lexer.LPAREN){
+		return nil
+	}
+
+	args := p.parseExpressionList(lexer.RPAREN)
+	if args != nil && len(args) != want{
+		msg := fmt.Sprintf("wrong number of arguments to %s in select.got =%d,want=%d",name,len(args),want)
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+
+	return args
+}
@@ -5,6 +5,7 @@ import (
 	"lexer"
 	"ast"
 	"fmt"
+	"strings"
 )
 
 func TestParser_ParseProgram(t *testing.T) {
@@ -449,7 +450,7 @@ func TestParser_FunctionLiteral(t *testing.T){
 	tests := []struct{
 		input string
 	}{
-		{"fn(){return x,y}"},
+		{"fn(){return x + y}"},
 		//{"fan(){}"},
 		//{"fan(x,y){}"},
 	}
@@ -475,6 +476,659 @@ func TestParser_FunctionLiteral(t *testing.T){
 			t.Fatalf("expected functional expression,but got=%T",stmt.Expression)
 		}
 
-		t.Errorf(exp.String())
+		if len(exp.Parameters) != 0{
+			t.Errorf("expected 0 parameters,but got=%d", len(exp.Parameters))
+		}
+
+		if exp.Body == nil || len(exp.Body.Statements) == 0{
+			t.Errorf("expected function body,but got=%s",exp.String())
+		}
+	}
+}
+
+func TestParser_HashLiteralOrder(t *testing.T){
+	input := `{"c":1,"a":2,"b":3}`
+
+	l := lexer.New(input)
+	p := New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t,p)
+
+	stmt,ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok{
+		t.Fatalf("expected ast.expressionStatement,but got=%T",program.Statements[0])
+	}
+
+	hash,ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok{
+		t.Fatalf("expected hash literal,but got=%T",stmt.Expression)
+	}
+
+	expectedKeys := []string{"c","a","b"}
+	if len(hash.Pairs) != len(expectedKeys){
+		t.Fatalf("expected %d pairs,but got=%d",len(expectedKeys),len(hash.Pairs))
+	}
+
+	for i,key := range expectedKeys{
+		if hash.Pairs[i].Key.String() != key{
+			t.Errorf("pair %d: expected key %s,but got=%s",i,key,hash.Pairs[i].Key.String())
+		}
+	}
+
+	if hash.String() != "{c:1,a:2,b:3}"{
+		t.Errorf("hash.String wrong,got=%s",hash.String())
+	}
+}
+
+func TestParser_TupleLiteral(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+		size int
+	}{
+		{"()","()",0},
+		{"(1,)","(1,)",1},
+		{"(1,2)","(1,2)",2},
+		{"(1 + 2,a,(b,c))","((1+2),a,(b,c))",3},
+	}
+
+	for _,tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		stmt,ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok{
+			t.Fatalf("expected ast.expressionStatement,but got=%T",program.Statements[0])
+		}
+
+		tuple,ok := stmt.Expression.(*ast.TupleLiteral)
+		if !ok{
+			t.Fatalf("expected tuple literal,but got=%T",stmt.Expression)
+		}
+
+		if len(tuple.Element) != tt.size{
+			t.Errorf("expected %d elements,but got=%d",tt.size,len(tuple.Element))
+		}
+
+		if tuple.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,tuple.String())
+		}
+	}
+}
+
+func TestParser_SetLiteral(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"{1}","{1}"},
+		{"{1,2,a + b}","{1,2,(a+b)}"},
+	}
+
+	for _,tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		stmt,ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok{
+			t.Fatalf("expected ast.expressionStatement,but got=%T",program.Statements[0])
+		}
+
+		set,ok := stmt.Expression.(*ast.SetLiteral)
+		if !ok{
+			t.Fatalf("expected set literal,but got=%T",stmt.Expression)
+		}
+
+		if set.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,set.String())
+		}
+	}
+}
+
+func TestParser_Struct(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"struct Point { x, y }","struct Point{x,y}"},
+		{"struct Empty {}","struct Empty{}"},
+		{"p.x","p.x"},
+		{"p.x.y","p.x.y"},
+		{"-p.x","(-p.x)"},
+		{"p.x + q.y","(p.x+q.y)"},
+		{"p.x = 1 + 2","p.x=(1+2)"},
+		{"a.x = b.y = 1","a.x=b.y=1"},
+		{"s.upper()","s.upper()"},
+		{"a.push(1, 2).len()","a.push(1,2).len()"},
+		{"-a.b(c)","(-a.b(c))"},
+	}
+
+	for _,tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if len(program.Statements) != 1{
+			t.Fatalf("%s: expected 1 statement,but got=%d",tt.input,len(program.Statements))
+		}
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+}
+
+//只有已知的结构体名字后面的{是结构体字面量
+func TestParser_StructLiteral(t *testing.T){
+	tests := []struct{
+		input string
+		declared []string
+		expected []string
+	}{
+		{"struct Point { x, y }; Point{x: 1, y: a + b}",nil,
+			[]string{"struct Point{x,y}","Point{x:1,y:(a+b)}"}},
+		{"Point{x: 1}",[]string{"Point"},[]string{"Point{x:1}"}},
+		{"p + Point{x: 1}",[]string{"Point"},[]string{"(p+Point{x:1})"}},
+		{"a {1}",nil,[]string{"a","{1}"}},
+		{"Point{x: 1}",nil,[]string{"Point","{x:1}"}},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+		p.DeclareStructs(tt.declared...)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if len(program.Statements) != len(tt.expected){
+			t.Fatalf("%s: expected %d statements,but got=%d",tt.input,len(tt.expected),len(program.Statements))
+		}
+		for i,stmt := range program.Statements{
+			if stmt.String() != tt.expected[i]{
+				t.Errorf("%s: expected %s,but got=%s",tt.input,tt.expected[i],stmt.String())
+			}
+		}
+	}
+
+	p := New(lexer.New("struct B { x }; struct A { y }"))
+	p.DeclareStructs("C")
+	p.ParseProgram()
+	if names := strings.Join(p.Structs(),","); names != "A,B,C"{
+		t.Errorf("expected structs A,B,C,got %s",names)
+	}
+}
+
+func TestParser_InvalidAssignment(t *testing.T){
+	l := lexer.New("1 = 2")
+	p := New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0{
+		t.Fatalf("expected parse error for invalid assignment target")
+	}
+}
+
+func TestParser_IllegalToken(t *testing.T){
+	p := New(lexer.New(`let s = "${x`))
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != `illegal token "${x`{
+		t.Fatalf("expected illegal token error,got %v",errors)
+	}
+}
+
+func TestParser_EnumAndMatch(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"enum Shape { Circle(r), Rect(w, h), Empty }","enum Shape{Circle(r),Rect(w,h),Empty}"},
+		{"Shape.Circle(1)","Shape.Circle(1)"},
+		{"match (s) { Circle(r) => r * r, _ => 0 }","match(s){Circle(r) => (r*r),_ => 0}"},
+		{"match (s) { Rect(w, h) if w == h => w, Shape.Empty => 0 }","match(s){Rect(w,h) if (w==h) => w,Shape.Empty => 0}"},
+		{"match (x) { 1 => a, -1 => b, \"s\" => c, true => d }","match(x){1 => a,(-1) => b,s => c,true => d}"},
+		{"match (t) { (a, _) => a, (x) => x }","match(t){(a,_) => a,x => x}"},
+		{"match (x) { Some(Some(v)) => { let y = v; y } n => n }","match(x){Some(Some(v)) => let y=v;y,n => n}"},
+		{"match (x) { _ => 1 } + 1","(match(x){_ => 1}+1)"},
+		{"match (x) { [a, ...rest] => a, [] => 0 }","match(x){[a,...rest] => a,[] => 0}"},
+	}
+
+	for _,tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if len(program.Statements) != 1{
+			t.Fatalf("%s: expected 1 statement,but got=%d",tt.input,len(program.Statements))
+		}
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+}
+
+func TestParser_InvalidPattern(t *testing.T){
+	inputs := []string{
+		"match (x) { [...a, b] => a }",
+		"match (x) { [...Some] => 0 }",
+		"let {\"a b\"} = x;",
+		"let {1: a} = x;",
+		"match (x) { a + 1 => a }",
+		"match (x) { a b }",
+	}
+
+	for _,input := range inputs{
+		p := New(lexer.New(input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0{
+			t.Errorf("%s:expected parse error",input)
+		}
+	}
+}
+
+func TestParser_LetPattern(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"let [a, b, ...rest] = arr;","let [a,b,...rest]=arr;"},
+		{"let [_, [x, y], ..._] = arr;","let [_,[x,y],..._]=arr;"},
+		{"let [] = arr;","let []=arr;"},
+		{"let {name, age: years} = person;","let {name,age:years}=person;"},
+		{"let {\"first name\": first, pos: [x, y]} = h;","let {first name:first,pos:[x,y]}=h;"},
+		{"let (a, b) = t;","let (a,b)=t;"},
+		{"let x = 1;","let x=1;"},
+	}
+
+	for _,tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if len(program.Statements) != 1{
+			t.Fatalf("%s: expected 1 statement,but got=%d",tt.input,len(program.Statements))
+		}
+
+		stmt,ok := program.Statements[0].(*ast.LetStatement)
+		if !ok{
+			t.Fatalf("expected *ast.LetStatement,got %T",program.Statements[0])
+		}
+		if stmt.Value == nil{
+			t.Errorf("%s:let value is nil",tt.input)
+		}
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+}
+
+func TestParser_Const(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"const x = 1;","const x=1;"},
+		{"const [a, b] = arr;","const [a,b]=arr;"},
+		{"const x = 1; let f = fn(){ let x = 2; x };","const x=1;let f=fn()let x=2;x;"},
+		{"let x = 1; const x = 2;","let x=1;const x=2;"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	invalid := []struct{
+		input string
+		expected string
+	}{
+		{"const x = 1; let x = 2;","cannot redeclare const x"},
+		{"const x = 1; const x = 2;","cannot redeclare const x"},
+		{"const {a, b: x} = h; let [y, x] = arr;","cannot redeclare const x"},
+		{"let f = fn(){ const y = 1; let y = 2; };","cannot redeclare const y"},
+	}
+
+	for _,tt := range invalid{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected{
+			t.Errorf("%s:expected error %q,got %v",tt.input,tt.expected,p.Errors())
+		}
+	}
+}
+
+func TestParser_TypeAnnotations(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"let x: int = 1;","let x:int=1;"},
+		{"let [a, b]: [int] = xs;","let [a,b]:[int]=xs;"},
+		{"let h: {string: [int]} = {};","let h:{string:[int]}={};"},
+		{"let s: {int} = set();","let s:{int}=set();"},
+		{"let t: (int, string) = (1, \"a\");","let t:(int,string)=(1,a);"},
+		{"let t: (int,) = (1,);","let t:(int,)=(1,);"},
+		{"let t: ((int)) = 1;","let t:int=1;"},
+		{"let f: fn(int, string) -> bool = g;","let f:fn(int,string)->bool=g;"},
+		{"let f: fn() = g;","let f:fn()=g;"},
+		{"fn(a: int, b: string) -> bool { a }","fn(a:int,b:string)->boola"},
+		{"fn(a, b: [int]) { a }","fn(a,b:[int])a"},
+		{"fn(f: fn(int) -> int) -> fn(int) -> int { f }","fn(f:fn(int)->int)->fn(int)->intf"},
+		{"fn() -> {string: int} { {} }","fn()->{string:int}{}"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	invalid := []string{
+		"let x: = 1;",
+		"let x: [int = 1;",
+		"fn(a: ) { a }",
+		"fn(a) -> { a }",
+		"enum E { A(x: int) }",
+	}
+
+	for _,input := range invalid{
+		p := New(lexer.New(input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0{
+			t.Errorf("%s:expected parse error",input)
+		}
+	}
+}
+
+func TestParser_ImportExport(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let m = import "lib/math";`,`let m=import "lib/math";`},
+		{`import "a".f(1)`,`import "a".f(1)`},
+		{"export let x = 1;","export let x=1;"},
+		{"export const [a, b] = arr;","export const [a,b]=arr;"},
+		{"export struct Point { x, y }","export struct Point{x,y}"},
+		{"export enum Color { Red, Green }","export enum Color{Red,Green}"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	invalid := []struct{
+		input string
+		expected string
+	}{
+		{"export 1;","cannot export 1"},
+		{"let f = fn(){ export let x = 1; };","export is only allowed at the top level"},
+		{"import math;","expected next token to be STRING,got INDENT instead"},
+	}
+
+	for _,tt := range invalid{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected{
+			t.Errorf("%s:expected error %q,got %v",tt.input,tt.expected,p.Errors())
+		}
+	}
+}
+
+func TestParser_InterpolatedString(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`"Hello ${name}!"`,"Hello ${name}!"},
+		{`"${a + b * 2}"`,"${(a+(b*2))}"},
+		{`"${f("x")}: ${"in ${y}"}"`,"${f(x)}: ${in ${y}}"},
+		{`let s = "n=${n}";`,"let s=n=${n};"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	invalid := []struct{
+		input string
+		expected string
+	}{
+		{`"${}"`,"empty interpolation in string"},
+		{`"${a b}"`,"invalid interpolation ${a b}:unexpected b"},
+		{`"${1 +}"`,"invalid interpolation ${1 +}:no prefix parse function for EOF found "},
+	}
+
+	for _,tt := range invalid{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected{
+			t.Errorf("%s:expected error %q,got %v",tt.input,tt.expected,p.Errors())
+		}
+	}
+}
+
+func TestParser_SliceExpression(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"a[1:2]","(a[1:2])"},
+		{"a[:b + 1]","(a[:(b+1)])"},
+		{"a[-1:]","(a[(-1):])"},
+		{"a[:]","(a[:])"},
+		{"a[1:][0]","((a[1:])[0])"},
+		{"f(x)[i:j].len()","(f(x)[i:j]).len()"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	p := New(lexer.New("a[1:2"))
+	p.ParseProgram()
+	if len(p.Errors()) == 0{
+		t.Errorf("expected an error for an unterminated slice")
+	}
+}
+
+func TestParser_RangeExpression(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"0..n","(0..n)"},
+		{"1..=10","(1..=10)"},
+		{"0..n - 1","(0..(n-1))"},
+		{"a + 1..b * 2","((a+1)..(b*2))"},
+		{"(0..10)[2:]","((0..10)[2:])"},
+		{"f(0..3)","f((0..3))"},
+		{"let r = 1e9;","let r=1e9;"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %s,but got=%s",tt.expected,program.String())
+		}
+	}
+
+	integers := []struct{
+		input string
+		expected int64
+	}{
+		{"1e9",1000000000},
+		{"25e2",2500},
+		{"7e0",7},
+		{"9e18",9000000000000000000},
+	}
+
+	for _,tt := range integers{
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		lit := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntergerLiteral)
+		if lit.Value != tt.expected{
+			t.Errorf("%s:expected %d,got %d",tt.input,tt.expected,lit.Value)
+		}
+	}
+
+	p := New(lexer.New("1e19"))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 || p.Errors()[0] != `could not parse "1e19" as integer`{
+		t.Errorf("expected an overflow error,got %v",p.Errors())
+	}
+}
+func TestParser_YieldDefer(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn*(){ yield 1 + 2 }","fn*()yield (1+2)"},
+		{"fn*(){ let x = yield; x }","fn*()let x=yield;x"},
+		{"fn*(){ fn(x){ yield x } }","fn*()fn(x)yield x"},
+		{"fn(){ defer f(1); 2 }","fn()defer f(1);2"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %q,but got=%q",tt.expected,program.String())
+		}
+	}
+
+	//只有fn*是生成器，内层的回调不是
+	p := New(lexer.New("fn*(){ fn(x){ yield x } }"))
+	program := p.ParseProgram()
+	checkParserErrors(t,p)
+	outer := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	inner := outer.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if !outer.IsGenerator || inner.IsGenerator{
+		t.Errorf("expected only the outer function to be a generator,got %v %v",outer.IsGenerator,inner.IsGenerator)
+	}
+
+	errors := []struct{
+		input string
+		expected string
+	}{
+		{"yield 1","yield outside generator"},
+		{"fn(){ yield 1 }","yield outside generator"},
+		{"defer f()","defer outside function"},
+	}
+
+	for _,tt := range errors{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected{
+			t.Errorf("%s:expected error %q,got %v",tt.input,tt.expected,p.Errors())
+		}
+	}
+}
+func TestParser_SpawnSelect(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"spawn f(1)","spawn f(1)"},
+		{"spawn fn(){ 1 }","spawn fn()1"},
+		{"spawn f(1) + 2","(spawn f(1)+2)"},
+		{"select { v = recv(c) => v, send(d, 1 + 2) => 0, _ => { 1 } }",
+			"select{v=recv(c) => v,send(d,(1+2)) => 0,_ => 1}"},
+		{"select { recv(c) => 1 }","select{recv(c) => 1}"},
+		{"select {}","select{}"},
+		{"let c: chan[int] = chan();","let c:chan[int]=chan();"},
+		{"let chan = 1;","let chan=1;"},
+	}
+
+	for _,tt := range tests{
+		p := New(lexer.New(tt.input))
+
+		program := p.ParseProgram()
+		checkParserErrors(t,p)
+
+		if program.String() != tt.expected{
+			t.Errorf("expected %q,but got=%q",tt.expected,program.String())
+		}
+	}
+
+	errors := []struct{
+		input string
+		expected string
+	}{
+		{"select { f(c) => 1 }","expected recv,send or _ in select,got f"},
+		{"select { v = send(c, 1) => 1 }","expected recv,send or _ in select,got send"},
+		{"select { recv(c, d) => 1 }","wrong number of arguments to recv in select.got =2,want=1"},
+		{"select { send(c) => 1 }","wrong number of arguments to send in select.got =1,want=2"},
+		{"select { _ => 1, _ => 2 }","multiple defaults in select"},
+	}
+
+	for _,tt := range errors{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected{
+			t.Errorf("%s:expected error %q,got %v",tt.input,tt.expected,p.Errors())
+		}
 	}
 }
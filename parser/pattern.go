@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"ast"
+	"fmt"
+	"lexer"
+)
+
+//解析当前token开始的模式
+func (p *Parser)parsePattern()ast.Pattern{
+	switch p.curToken.Type {
+	case lexer.INDENT:
+		return p.parseNamePattern()
+	case lexer.INT:
+		return &ast.LiteralPattern{Value:p.parseIntegerLiteral()}
+	case lexer.STRING:
+		return &ast.LiteralPattern{Value:p.parseStringLiteral()}
+	case lexer.TRUE,lexer.FALSE:
+		return &ast.LiteralPattern{Value:p.parseBoolean()}
+	case lexer.MINUS:
+		if !p.peekTokenis(lexer.INT){
+			p.patternError()
+			return nil
+		}
+		return &ast.LiteralPattern{Value:p.parsePrefixExpression()}
+	case lexer.LPAREN:
+		return p.parseTuplePattern()
+	case lexer.LBRACKET:
+		return p.parseArrayPattern()
+	case lexer.LBRACE:
+		return p.parseHashPattern()
+	default:
+		p.patternError()
+		return nil
+	}
+}
+
+//_是通配符，大写开头、带括号或者带枚举名的是枚举分支，其他的是绑定；
+//let的模式中大写开头的名字也是绑定
+func (p *Parser)parseNamePattern()ast.Pattern{
+	if p.curToken.Value == "_"{
+		return &ast.WildcardPattern{Token:p.curToken}
+	}
+
+	name := &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+	variant := &ast.VariantPattern{Token:p.curToken,Name:name}
+
+	if p.peekTokenis(lexer.DOT){
+		p.nextToken()
+		if !p.expectPeek(lexer.INDENT){
+			return nil
+		}
+		variant.Enum = name
+		variant.Name = &ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}
+	}else if (p.letPattern || !isVariantName(name.Value)) && !p.peekTokenis(lexer.LPAREN){
+		return &ast.BindingPattern{Name:name}
+	}
+
+	if p.peekTokenis(lexer.LPAREN){
+		p.nextToken()
+		variant.Fields = p.parsePatternList(lexer.RPAREN)
+		if variant.Fields == nil{
+			return nil
+		}
+	}
+
+	return variant
+}
+
+func isVariantName(name string)bool{
+	return name[0] >= 'A' && name[0] <= 'Z'
+}
+
+//(p)只是分组，()、(p,)和(p,q)是元组
+func (p *Parser)parseTuplePattern()ast.Pattern{
+	tuple := &ast.TuplePattern{Token:p.curToken,Elements:[]ast.Pattern{}}
+
+	if p.peekTokenis(lexer.RPAREN){
+		p.nextToken()
+		return tuple
+	}
+
+	p.nextToken()
+	first := p.parsePattern()
+
+	if !p.peekTokenis(lexer.COMMA){
+		if !p.expectPeek(lexer.RPAREN){
+			return nil
+		}
+
+		return first
+	}
+
+	tuple.Elements = append(tuple.Elements,first)
+	for p.peekTokenis(lexer.COMMA){
+		p.nextToken()
+		if p.peekTokenis(lexer.RPAREN){
+			break
+		}
+
+		p.nextToken()
+		tuple.Elements = append(tuple.Elements,p.parsePattern())
+	}
+
+	if !p.expectPeek(lexer.RPAREN){
+		return nil
+	}
+
+	return tuple
+}
+
+//[a,b,...rest]，...只能出现在最后
+func (p *Parser)parseArrayPattern()ast.Pattern{
+	array := &ast.ArrayPattern{Token:p.curToken,Elements:[]ast.Pattern{}}
+
+	for !p.peekTokenis(lexer.RBRACKET){
+		p.nextToken()
+
+		if p.curTokenis(lexer.ELLIPSIS){
+			if !p.expectPeek(lexer.INDENT){
+				return nil
+			}
+			array.Rest = p.parseNamePattern()
+			if _,ok := array.Rest.(*ast.VariantPattern);ok{
+				msg := fmt.Sprintf("invalid rest pattern %s",array.Rest.String())
+				p.errors = append(p.errors,msg)
+				return nil
+			}
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil{
+			return nil
+		}
+		array.Elements = append(array.Elements,element)
+
+		if !p.peekTokenis(lexer.RBRACKET) && !p.expectPeek(lexer.COMMA){
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACKET){
+		return nil
+	}
+
+	return array
+}
+
+//{name,age:years,"first name":first}
+func (p *Parser)parseHashPattern()ast.Pattern{
+	hash := &ast.HashPattern{Token:p.curToken,Entries:[]ast.HashPatternEntry{}}
+
+	for !p.peekTokenis(lexer.RBRACE){
+		p.nextToken()
+
+		if !p.curTokenis(lexer.INDENT) && !p.curTokenis(lexer.STRING){
+			p.patternError()
+			return nil
+		}
+		key := &ast.StringLiteral{Token:p.curToken,Value:p.curToken.Value}
+		entry := ast.HashPatternEntry{Key:key}
+
+		if p.peekTokenis(lexer.COLON){
+			p.nextToken()
+			p.nextToken()
+			entry.Value = p.parsePattern()
+			if entry.Value == nil{
+				return nil
+			}
+		}else if p.curTokenis(lexer.INDENT){
+			entry.Value = &ast.BindingPattern{Name:&ast.Indetifier{Token:p.curToken,Value:p.curToken.Value}}
+		}else{
+			msg := fmt.Sprintf("key %q in hash pattern needs a pattern",key.Value)
+			p.errors = append(p.errors,msg)
+			return nil
+		}
+		hash.Entries = append(hash.Entries,entry)
+
+		if !p.peekTokenis(lexer.RBRACE) && !p.expectPeek(lexer.COMMA){
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return hash
+}
+
+func (p *Parser)parsePatternList(end lexer.TokenType)[]ast.Pattern{
+	list := []ast.Pattern{}
+
+	if p.peekTokenis(end){
+		p.nextToken()
+		return list
+	}
+
+	p.nextToken()
+	list = append(list,p.parsePattern())
+
+	for p.peekTokenis(lexer.COMMA){
+		p.nextToken()
+		p.nextToken()
+		list = append(list,p.parsePattern())
+	}
+
+	if !p.expectPeek(end){
+		return nil
+	}
+
+	return list
+}
+
+func (p *Parser)patternError(){
+	msg := fmt.Sprintf("unexpected %s in pattern",p.curToken.Type)
+	p.errors = append(p.errors,msg)
+}
@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"ast"
+	"fmt"
+	"lexer"
+)
+
+//ParseType解析单独的类型标注，比如"fn([a]) -> a"
+func ParseType(input string)(ast.TypeExpr,[]string){
+	p := New(lexer.New(input))
+
+	t := p.parseType()
+	if t != nil && !p.peekTokenis(lexer.EOF){
+		p.errors = append(p.errors,fmt.Sprintf("unexpected %s after type",p.peekToken.Type))
+	}
+
+	return t,p.Errors()
+}
+
+//解析当前token开始的类型
+func (p *Parser)parseType()ast.TypeExpr{
+	switch p.curToken.Type {
+	case lexer.INDENT:
+		if p.curToken.Value == "chan" && p.peekTokenis(lexer.LBRACKET){
+			return p.parseChannelType()
+		}
+		return &ast.NamedType{Token:p.curToken,Name:p.curToken.Value}
+	case lexer.LBRACKET:
+		return p.parseArrayType()
+	case lexer.LBRACE:
+		return p.parseHashOrSetType()
+	case lexer.LPAREN:
+		return p.parseTupleType()
+	case lexer.FUNCTION:
+		return p.parseFunctionType()
+	default:
+		msg := fmt.Sprintf("unexpected %s in type",p.curToken.Type)
+		p.errors = append(p.errors,msg)
+		return nil
+	}
+}
+
+//: T，当前token是冒号前面的名字
+func (p *Parser)parseTypeAnnotation()(ast.TypeExpr,bool){
+	if !p.peekTokenis(lexer.COLON){
+		return nil,true
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	t := p.parseType()
+	return t,t != nil
+}
+
+func (p *Parser)parseArrayType()ast.TypeExpr{
+	array := &ast.ArrayType{Token:p.curToken}
+
+	p.nextToken()
+	if array.Element = p.parseType();array.Element == nil{
+		return nil
+	}
+
+	if !p.expectPeek(lexer.RBRACKET){
+		return nil
+	}
+
+	return array
+}
+
+//chan[T]，当前token是chan
+func (p *Parser)parseChannelType()ast.TypeExpr{
+	channel := &ast.ChannelType{Token:p.curToken}
+
+	p.nextToken()
+	p.nextToken()
+	if channel.Element = p.parseType();channel.Element == nil{
+		return nil
+	}
+
+	if !p.expectPeek(lexer.RBRACKET){
+		return nil
+	}
+
+	return channel
+}
+
+//{K:V}是hash，{T}是集合
+func (p *Parser)parseHashOrSetType()ast.TypeExpr{
+	tok := p.curToken
+
+	p.nextToken()
+	key := p.parseType()
+	if key == nil{
+		return nil
+	}
+
+	if !p.peekTokenis(lexer.COLON){
+		if !p.expectPeek(lexer.RBRACE){
+			return nil
+		}
+		return &ast.SetType{Token:tok,Element:key}
+	}
+
+	p.nextToken()
+	p.nextToken()
+	value := p.parseType()
+	if value == nil || !p.expectPeek(lexer.RBRACE){
+		return nil
+	}
+
+	return &ast.HashType{Token:tok,Key:key,Value:value}
+}
+
+//(T)只是分组，(T,)和(T,U)是元组
+func (p *Parser)parseTupleType()ast.TypeExpr{
+	tuple := &ast.TupleType{Token:p.curToken}
+
+	elements,trailingComma := p.parseTypeList(lexer.RPAREN)
+	if elements == nil{
+		return nil
+	}
+
+	if len(elements) == 1 && !trailingComma{
+		return elements[0]
+	}
+	tuple.Elements = elements
+
+	return tuple
+}
+
+func (p *Parser)parseFunctionType()ast.TypeExpr{
+	fn := &ast.FunctionType{Token:p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN){
+		return nil
+	}
+
+	if fn.Parameters,_ = p.parseTypeList(lexer.RPAREN);fn.Parameters == nil{
+		return nil
+	}
+
+	if p.peekTokenis(lexer.ARROW){
+		p.nextToken()
+		p.nextToken()
+		if fn.Return = p.parseType();fn.Return == nil{
+			return nil
+		}
+	}
+
+	return fn
+}
+
+//当前token是开始的括号，允许最后有一个逗号，第二个返回值表示有没有
+func (p *Parser)parseTypeList(end lexer.TokenType)([]ast.TypeExpr,bool){
+	list := []ast.TypeExpr{}
+	trailingComma := false
+
+	for !p.peekTokenis(end){
+		p.nextToken()
+
+		t := p.parseType()
+		if t == nil{
+			return nil,false
+		}
+		list = append(list,t)
+
+		trailingComma = false
+		if !p.peekTokenis(end){
+			if !p.expectPeek(lexer.COMMA){
+				return nil,false
+			}
+			trailingComma = true
+		}
+	}
+
+	if !p.expectPeek(end){
+		return nil,false
+	}
+
+	return list,trailingComma
+}
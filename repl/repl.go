@@ -2,11 +2,13 @@ package repl
 
 import (
 	"io"
+	"os"
 	"bufio"
 	"fmt"
 	"lexer"
 	"parser"
 	"evaluator"
+	"checker"
 )
 
 const PROMPT = ">>"
@@ -14,7 +16,13 @@ const PROMPT = ">>"
 func Start(in io.Reader,out io.Writer){
 	scanner := bufio.NewScanner(in)
 
-	env := evaluator.NewEnvironment()
+	env := evaluator.NewRootEnvironment()
+	//import从当前目录查找模块
+	env.SetModuleLoader(evaluator.NewModuleLoader(os.DirFS(".")))
+	env.SetOutput(out)
+	types := checker.NewTypeChecker()
+	//之前的行声明的结构体，后面的行中P{...}才是结构体字面量
+	structs := []string{}
 	for{
 		fmt.Printf(PROMPT)
 
@@ -26,6 +34,7 @@ func Start(in io.Reader,out io.Writer){
 		line := scanner.Text()
 		l := lexer.New(line)
 		p := parser.New(l)
+		p.DeclareStructs(structs...)
 
 		program := p.ParseProgram()
 
@@ -33,9 +42,22 @@ func Start(in io.Reader,out io.Writer){
 			fmt.Printf("parse error:%v",p.Errors())
 			continue
 		}
+		structs = p.Structs()
+
+		if errors := types.Check(program);len(errors) != 0{
+			for _,err := range errors{
+				io.WriteString(out,"type error:" + err + "\n")
+			}
+			continue
+		}
+
+		for _,warning := range checker.Check(program,evaluator.BuiltinNames(),env.Names()){
+			io.WriteString(out,"warning:" + warning + "\n")
+		}
 
 		evaluated := evaluator.Eval(program,env)
-		if evaluated != nil{
+		//print等没有值的表达式不回显null
+		if evaluated != nil && evaluated != evaluator.NULL{
 			io.WriteString(out,evaluated.Inspect())
 			io.WriteString(out,"\n")
 		}
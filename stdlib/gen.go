@@ -0,0 +1,110 @@
+//go:build ignore
+
+//预编译标准库：解析每个.monkey文件，把得到的语法树写成Go代码(precompiled.go)，
+//启动时直接使用，不再词法分析和解析标准库的源码
+//在stdlib目录下由go generate运行
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"lexer"
+	"os"
+	"parser"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main(){
+	files,err := filepath.Glob("*.monkey")
+	if err != nil{
+		fail(err)
+	}
+	sort.Strings(files)
+
+	var out strings.Builder
+	out.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	out.WriteString("package stdlib\n\n")
+	out.WriteString("import (\n\t\"ast\"\n\t\"lexer\"\n)\n\n")
+	out.WriteString("//每个模块预先解析好的语法树，key是去掉扩展名的文件名\n")
+	out.WriteString("var Programs = map[string]*ast.Program{\n")
+
+	for _,file := range files{
+		src,err := os.ReadFile(file)
+		if err != nil{
+			fail(err)
+		}
+
+		p := parser.New(lexer.New(string(src)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0{
+			fail(fmt.Errorf("%s:%s",file,strings.Join(p.Errors(),";")))
+		}
+
+		name := strings.TrimSuffix(file,".monkey")
+		fmt.Fprintf(&out,"%s:%s,\n",strconv.Quote(name),literal(reflect.ValueOf(program)))
+	}
+	out.WriteString("}\n")
+
+	src,err := format.Source([]byte(out.String()))
+	if err != nil{
+		fail(err)
+	}
+	if err := os.WriteFile("precompiled.go",src,0644);err != nil{
+		fail(err)
+	}
+}
+
+//语法树节点的Go字面量，零值的字段省略
+func literal(v reflect.Value)string{
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil(){
+			return "nil"
+		}
+		return "&" + literal(v.Elem())
+	case reflect.Interface:
+		if v.IsNil(){
+			return "nil"
+		}
+		return literal(v.Elem())
+	case reflect.Struct:
+		fields := []string{}
+		for i := 0;i < v.NumField();i++{
+			if v.Field(i).IsZero(){
+				continue
+			}
+			fields = append(fields,v.Type().Field(i).Name + ":" + literal(v.Field(i)))
+		}
+		return v.Type().String() + "{" + strings.Join(fields,",") + "}"
+	case reflect.Slice:
+		if v.IsNil(){
+			return "nil"
+		}
+		if v.Len() == 0{
+			return v.Type().String() + "{}"
+		}
+		elements := []string{}
+		for i := 0;i < v.Len();i++{
+			elements = append(elements,literal(v.Index(i)))
+		}
+		return v.Type().String() + "{\n" + strings.Join(elements,",\n") + ",\n}"
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Int,reflect.Int64:
+		return strconv.FormatInt(v.Int(),10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+
+	fail(fmt.Errorf("cannot precompile %s",v.Type()))
+	return ""
+}
+
+func fail(err error){
+	fmt.Fprintln(os.Stderr,"stdlib:",err)
+	os.Exit(1)
+}
@@ -0,0 +1,636 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package stdlib
+
+import (
+	"ast"
+	"lexer"
+)
+
+// 每个模块预先解析好的语法树，key是去掉扩展名的文件名
+var Programs = map[string]*ast.Program{
+	"collections": &ast.Program{Statements: []ast.Statement{
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "sum"}, Value: "sum"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}, Operator: "+", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}}},
+				}}},
+				&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "product"}, Value: "product"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "*", Value: "*"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}, Operator: "*", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}}},
+				}}},
+				&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "max"}, Value: "max"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: ">", Value: ">"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			}}, Operator: ">", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "rest"}, Value: "rest"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					}},
+					&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}, ParameterTypes: []ast.TypeExpr{
+						nil,
+						nil,
+					}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: ">", Value: ">"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}, Operator: ">", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+							&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "x"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}},
+						}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+							&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+						}}}},
+					}}},
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "first"}, Value: "first"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					}},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "min"}, Value: "min"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: ">", Value: ">"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			}}, Operator: ">", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "rest"}, Value: "rest"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					}},
+					&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}, ParameterTypes: []ast.TypeExpr{
+						nil,
+						nil,
+					}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}, Operator: "<", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+							&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "x"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}},
+						}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+							&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+						}}}},
+					}}},
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "first"}, Value: "first"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					}},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "count"}, Value: "count"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pred"}, Value: "pred"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pred"}, Value: "pred"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}, Operator: "+", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1}}},
+					}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+					}}}},
+				}}},
+				&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "find"}, Value: "find"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pred"}, Value: "pred"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "matched"}, Value: "matched"}, Value: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "filter"}, Value: "filter"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pred"}, Value: "pred"},
+			}}},
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: ">", Value: ">"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "matched"}, Value: "matched"},
+			}}, Operator: ">", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "first"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "first"}, Value: "first"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "matched"}, Value: "matched"},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "take"}, Value: "take"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"}, Operator: "<", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "slice"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "slice"}, Value: "slice"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"},
+				}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "drop"}, Value: "drop"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"}, Operator: "<", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "slice"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "slice"}, Value: "slice"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"},
+				}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "[", Value: "["}, Expression: &ast.ArrayLiteral{Element: []ast.Expression{}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "chunk"}, Value: "chunk"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"}, Operator: "<", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ReturnStatement{Token: lexer.Token{Type: "return", Value: "return"}, ReturnValue: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "error"}, Value: "error"}, Arguments: []ast.Expression{
+					&ast.InterpolatedString{Token: lexer.Token{Type: "INTERP_STRING", Value: "chunk: size must be positive,got ${size}"}, Parts: []ast.Expression{
+						&ast.StringLiteral{Token: lexer.Token{Type: "STRING", Value: "chunk: size must be positive,got "}, Value: "chunk: size must be positive,got "},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"},
+					}},
+				}}},
+			}}}},
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "==", Value: "=="}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			}}, Operator: "==", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "[", Value: "["}, Expression: &ast.ArrayLiteral{Element: []ast.Expression{}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "concat"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "concat"}, Value: "concat"}, Arguments: []ast.Expression{
+					&ast.ArrayLiteral{Element: []ast.Expression{
+						&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "take"}, Value: "take"}, Arguments: []ast.Expression{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"},
+						}},
+					}},
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "chunk"}, Value: "chunk"}, Arguments: []ast.Expression{
+						&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "drop"}, Value: "drop"}, Arguments: []ast.Expression{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"},
+						}},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "size"}, Value: "size"},
+					}},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "flatten"}, Value: "flatten"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "concat"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "concat"}, Value: "concat"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}}},
+				}}},
+				&ast.ArrayLiteral{Element: []ast.Expression{}},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "zip"}, Value: "zip"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"},
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}, Operator: "<", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+				}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}, Operator: "<", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+					}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Arguments: []ast.Expression{
+							&ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}, Operator: "+", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1}},
+							&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "push"}, Value: "push"}, Arguments: []ast.Expression{
+								&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+								&ast.TupleLiteral{Token: lexer.Token{Type: "(", Value: "("}, Element: []ast.Expression{
+									&ast.IndexExpression{Token: lexer.Token{Type: "[", Value: "["}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"}, Index: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}},
+									&ast.IndexExpression{Token: lexer.Token{Type: "[", Value: "["}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"}, Index: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}},
+								}},
+							}},
+						}}},
+					}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+					}}}},
+				}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+				}}}},
+			}}}},
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Arguments: []ast.Expression{
+				&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+				&ast.ArrayLiteral{Element: []ast.Expression{}},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "uniq"}, Value: "uniq"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "any"}, Value: "any"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "y"}, Value: "y"},
+						}, ParameterTypes: []ast.TypeExpr{
+							nil,
+						}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+							&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "y"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "==", Value: "=="}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "y"}, Value: "y"}, Operator: "==", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}}},
+						}}},
+					}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+					}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "push"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "push"}, Value: "push"}, Arguments: []ast.Expression{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+						}}},
+					}}}},
+				}}},
+				&ast.ArrayLiteral{Element: []ast.Expression{}},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "group_by"}, Value: "group_by"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "key"}, Value: "key"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "arr"}, Value: "arr"},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "k"}, Value: "k"}, Value: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "key"}, Value: "key"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}}},
+					&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "group"}, Value: "group"}, Value: &ast.IfExpression{Condition: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "has"}, Value: "has"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "k"}, Value: "k"},
+					}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.IndexExpression{Token: lexer.Token{Type: "[", Value: "["}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}, Index: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "k"}, Value: "k"}}},
+					}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "[", Value: "["}, Expression: &ast.ArrayLiteral{Element: []ast.Expression{}}},
+					}}}},
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "merge"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "merge"}, Value: "merge"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.HashLiteral{Token: lexer.Token{Type: "{", Value: "{"}, Pairs: []ast.HashLiteralPair{
+							ast.HashLiteralPair{Key: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "k"}, Value: "k"}, Value: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "concat"}, Value: "concat"}, Arguments: []ast.Expression{
+								&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "group"}, Value: "group"},
+								&ast.ArrayLiteral{Element: []ast.Expression{
+									&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+								}},
+							}}},
+						}},
+					}}},
+				}}},
+				&ast.HashLiteral{Token: lexer.Token{Type: "{", Value: "{"}, Pairs: []ast.HashLiteralPair{}},
+			}}},
+		}}}}},
+	}},
+	"functional": &ast.Program{Statements: []ast.Statement{
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "identity"}, Value: "identity"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "x"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "constant"}, Value: "constant"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Expression: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "_"}, Value: "_"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "x"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "compose"}, Value: "compose"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "g"}, Value: "g"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Expression: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "f"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"}, Arguments: []ast.Expression{
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "g"}, Value: "g"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+					}},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pipe"}, Value: "pipe"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "fns"}, Value: "fns"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Expression: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "reduce"}, Value: "reduce"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "fns"}, Value: "fns"},
+					&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"},
+					}, ParameterTypes: []ast.TypeExpr{
+						nil,
+						nil,
+					}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "f"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"}, Arguments: []ast.Expression{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+						}}},
+					}}},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "x"}, Value: "x"},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "flip"}, Value: "flip"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Expression: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "f"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "partial"}, Value: "partial"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Expression: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "f"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "a"}, Value: "a"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "b"}, Value: "b"},
+				}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "times"}, Value: "times"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"},
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+			}, ParameterTypes: []ast.TypeExpr{
+				nil,
+				nil,
+			}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}, Operator: "<", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "n"}, Value: "n"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Arguments: []ast.Expression{
+						&ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"}, Operator: "+", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1}},
+						&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "push"}, Value: "push"}, Arguments: []ast.Expression{
+							&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"},
+							&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "f"}, Value: "f"}, Arguments: []ast.Expression{
+								&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "i"}, Value: "i"},
+							}},
+						}},
+					}}},
+				}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "acc"}, Value: "acc"}},
+				}}}},
+			}}}},
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "loop"}, Value: "loop"}, Arguments: []ast.Expression{
+				&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+				&ast.ArrayLiteral{Element: []ast.Expression{}},
+			}}},
+		}}}}},
+	}},
+	"strings": &ast.Program{Statements: []ast.Statement{
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pad_left"}, Value: "pad_left"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			}}, Operator: "<", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "repeat"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "repeat"}, Value: "repeat"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+					&ast.InfixExpression{Token: lexer.Token{Type: "-", Value: "-"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}, Operator: "-", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+					}}},
+				}}, Operator: "+", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "s"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pad_right"}, Value: "pad_right"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			}}, Operator: "<", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "s"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}, Operator: "+", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "repeat"}, Value: "repeat"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+					&ast.InfixExpression{Token: lexer.Token{Type: "-", Value: "-"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}, Operator: "-", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+					}}},
+				}}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "s"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "center"}, Value: "center"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"},
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+			nil,
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "<", Value: "<"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			}}, Operator: "<", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "left"}, Value: "left"}, Value: &ast.InfixExpression{Token: lexer.Token{Type: "/", Value: "/"}, Left: &ast.InfixExpression{Token: lexer.Token{Type: "-", Value: "-"}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"}, Operator: "-", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+				}}}, Operator: "/", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "2"}, Value: 2}}},
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "pad_right"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "pad_right"}, Value: "pad_right"}, Arguments: []ast.Expression{
+					&ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "repeat"}, Value: "repeat"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "left"}, Value: "left"},
+					}}, Operator: "+", Right: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "width"}, Value: "width"},
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "ch"}, Value: "ch"},
+				}}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "s"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "capitalize"}, Value: "capitalize"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "if", Value: "if"}, Expression: &ast.IfExpression{Condition: &ast.InfixExpression{Token: lexer.Token{Type: "==", Value: "=="}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "len"}, Value: "len"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+			}}, Operator: "==", Right: &ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}}}, Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "s"}, Expression: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"}},
+			}}, Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "upper"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "+", Value: "+"}, Left: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "upper"}, Value: "upper"}, Arguments: []ast.Expression{
+					&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "substr"}, Value: "substr"}, Arguments: []ast.Expression{
+						&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+						&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "0"}},
+						&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1},
+					}},
+				}}, Operator: "+", Right: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "substr"}, Value: "substr"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+					&ast.IntergerLiteral{Token: lexer.Token{Type: "INT", Value: "1"}, Value: 1},
+				}}}},
+			}}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "lines"}, Value: "lines"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "split"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "split"}, Value: "split"}, Arguments: []ast.Expression{
+				&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+				&ast.StringLiteral{Token: lexer.Token{Type: "STRING", Value: "\n"}, Value: "\n"},
+			}}},
+		}}}}},
+		&ast.ExportStatement{Token: lexer.Token{Type: "export", Value: "export"}, Statement: &ast.LetStatement{Token: lexer.Token{Type: "LET", Value: "let"}, Name: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "words"}, Value: "words"}, Value: &ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+			&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+		}, ParameterTypes: []ast.TypeExpr{
+			nil,
+		}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "filter"}, Expression: &ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "filter"}, Value: "filter"}, Arguments: []ast.Expression{
+				&ast.CallExpression{Token: lexer.Token{Type: "(", Value: "("}, Function: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "split"}, Value: "split"}, Arguments: []ast.Expression{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "s"}, Value: "s"},
+					&ast.StringLiteral{Token: lexer.Token{Type: "STRING", Value: " "}, Value: " "},
+				}},
+				&ast.FunctionLiteral{Token: lexer.Token{Type: "FUNCTION", Value: "fn"}, Parameters: []*ast.Indetifier{
+					&ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "w"}, Value: "w"},
+				}, ParameterTypes: []ast.TypeExpr{
+					nil,
+				}, Body: &ast.BlockStatement{Statements: []ast.Statement{
+					&ast.ExpressionStatement{Token: lexer.Token{Type: "INDENT", Value: "w"}, Expression: &ast.InfixExpression{Token: lexer.Token{Type: "!=", Value: "!="}, Left: &ast.Indetifier{Token: lexer.Token{Type: "INDENT", Value: "w"}, Value: "w"}, Operator: "!=", Right: &ast.StringLiteral{Token: lexer.Token{Type: "STRING"}}}},
+				}}},
+			}}},
+		}}}}},
+	}},
+}
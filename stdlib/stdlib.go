@@ -0,0 +1,13 @@
+//标准库，用语言本身写成，编译时嵌入到二进制中
+//每个.monkey文件是一个模块，启动时把它们导出的名字加载到根环境
+//增加库函数只需要在这个目录下添加或修改.monkey文件，然后运行go generate：
+//gen.go把解析好的语法树生成到precompiled.go，启动时不用再解析源码，
+//没有预编译的文件仍然从源码加载
+package stdlib
+
+import "embed"
+
+//go:generate go run gen.go
+
+//go:embed *.monkey
+var FS embed.FS